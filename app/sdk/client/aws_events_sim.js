@@ -85,8 +85,8 @@ async function setupChaincodeListener(){
 
             var object = JSON.parse(newAssetEvent.payload)
             console.log(object.assetid)
-            console.log(object.sharetime)
-            console.log(object.accesstime)
+            console.log(object.timestamp)
+            console.log(object.body.createtime)
 
             console.log('Event receipt time ' + Math.round((new Date()).getTime() / 1000))
         },
@@ -109,8 +109,8 @@ async function setupChaincodeListener(){
             ${new String(assetModifiedEvent.payload)}`)
 
             var object = JSON.parse(assetModifiedEvent.payload)
-            console.log(object.source)
-            var d = object.dependents
+            console.log(object.assetid)
+            var d = object.body.dependents
             console.log(d.length)
         },
         ()=> {
@@ -12,6 +12,7 @@
  */
 
 'use strict';
+const crypto = require('crypto');
 const fs = require('fs');
 const Client = require('fabric-client');
 
@@ -30,6 +31,11 @@ const CHANNEL_NAME = 'oem-channel'
 const CHAINCODE_ID = 'oemcc'
 // const CHAINCODE_EVENT = 'transfer'
 
+// Organization signing off the requirements before they are shared
+const APPROVER_ORG_NAME = 'designgroup.oem.com'
+const APPROVER_PEER_NAME = 'peer0.designgrp.oem.com'
+const APPROVER_MSP = 'DesignGroupMSP'
+
 
 // Variable to hold the client
 var client = {}
@@ -40,10 +46,10 @@ main()
 
 async function main(){
     // setup client
-    client = await setupClient()
+    client = await setupClient(ORG_NAME)
 
     // Setup the channel instance
-    channel = await setupChannel()
+    channel = await setupChannel(client)
 
     const DATA_FILE_PATH = '../data/Data_128'
 
@@ -62,23 +68,125 @@ async function main(){
     //     await createAssets("Req-1" + i, data.toString())
     // }
 
+    // Drafts cannot be shared, they are submitted for review and approved first
+    await approveAssets(assets)
+
     // Share assets
     await shareAssets(assets)
 
     console.log('Transaction end time ' + Math.round((new Date()).getTime() / 1000))
 }
 
+function sleep(ms) {
+    return new Promise(resolve => setTimeout(resolve, ms));
+}
+
+/**
+ * Takes the assets through review: the owner submits each one for approval by APPROVER_MSP,
+ * then an admin of the approver organization signs it off. The requirement is approved once
+ * the approval commits.
+ */
+async function approveAssets(assets) {
+
+    for (var i = 0; i < assets.length; i++) {
+        await sendTransaction(client, channel, PEER_NAME, "RequestApproval",
+            [assets[i], JSON.stringify([APPROVER_MSP]), "0"])
+    }
+
+    // Wait for the requests to commit before signing them off
+    await sleep(5000)
+
+    let approverClient = await setupClient(APPROVER_ORG_NAME)
+    let approverChannel = await setupChannel(approverClient)
+
+    for (var i = 0; i < assets.length; i++) {
+        await sendTransaction(approverClient, approverChannel, APPROVER_PEER_NAME, "Approve", [assets[i], ""])
+    }
+
+    // Wait for the approvals to commit before sharing
+    await sleep(5000)
+}
+
+/**
+ * Sends a transaction proposal to the named peer and broadcasts the endorsed transaction
+ */
+async function sendTransaction(txClient, txChannel, peer, fcn, args) {
+
+    let peerName = txChannel.getChannelPeer(peer)
+
+    var tx_id = txClient.newTransactionID();
+
+    var request = {
+        targets: peerName,
+        chaincodeId: CHAINCODE_ID,
+        fcn: fcn,
+        args: args,
+        chainId: CHANNEL_NAME,
+        txId: tx_id
+    };
+
+    let results = await txChannel.sendTransactionProposal(request);
+
+    // Array of proposal responses *or* error @ index=0
+    var proposalResponses = results[0];
+
+    // Original proposal @ index = 1
+    var proposal = results[1];
+
+     // Broadcast request
+     var orderer_request = {
+        txId: tx_id,
+        proposalResponses: proposalResponses,
+        proposal: proposal
+    };
+
+    await txChannel.sendTransaction(orderer_request);
+}
+
+/**
+ * Reads the current text of each asset from the owner's private collection. The suppliers
+ * endorse sharing as well and cannot read that collection, so the texts travel in the
+ * transient map and the chaincode checks them against the public hashes.
+ */
+async function readContents(assets) {
+
+    var contents = []
+
+    for (var i = 0; i < assets.length; i++) {
+        let asset = await channel.queryByChaincode({
+            chaincodeId: CHAINCODE_ID,
+            fcn: 'ReadAsset',
+            args: [assets[i]]
+        })
+
+        let revision = JSON.parse(asset[0].toString('utf8')).revision
+
+        let text = await channel.queryByChaincode({
+            chaincodeId: CHAINCODE_ID,
+            fcn: 'ReadContentText',
+            args: [assets[i], String(revision)]
+        })
+
+        contents.push(JSON.parse(text[0].toString('utf8')))
+    }
+
+    return contents
+}
+
 async function shareAssets(assets) {
 
     let peerName = channel.getChannelPeer(PEER_NAME)
 
+    let contents = await readContents(assets)
+
     var tx_id = client.newTransactionID();
 
     var request = {
         targets: peerName,
         chaincodeId: CHAINCODE_ID,
         fcn: "ShareAssetsBulk",
-        args: [JSON.stringify(assets),"{\"firstname\":\"Sam\",\"lastname\":\"Designer\"}"],
+        args: [JSON.stringify(assets),"SupplierMSP"],
+        transientMap: {contents: Buffer.from(JSON.stringify(contents))},
         chainId: CHANNEL_NAME,
         txId: tx_id
     };
@@ -117,7 +225,8 @@ async function createAssets(assetID, payload) {
         targets: peerName,
         chaincodeId: CHAINCODE_ID,
         fcn:'NewAsset',
-        args: [assetID,"{\"firstname\":\"John\",\"lastname\":\"Doe\"}",payload],
+        args: [assetID,'{}'],
+        transientMap: {text: Buffer.from(payload), salt: Buffer.from(crypto.randomBytes(16).toString('hex'))},
         chainId: CHANNEL_NAME,
         txId: tx_id
     };
@@ -157,18 +266,18 @@ async function createAssets(assetID, payload) {
  * 4. Loads the user from credential store
  * 5. Sets the user on client instance and returns it
  */
-async function setupClient() {
+async function setupClient(orgName) {
 
     // setup the instance
     const client = Client.loadFromConfig(CONNECTION_PROFILE_PATH)
 
     // setup the client part
-    if (ORG_NAME == 'requirements.oem.com') {
+    if (orgName == 'requirements.oem.com') {
         client.loadFromConfig(REQ_CLIENT_CONNECTION_PROFILE_PATH)
-    } else if (ORG_NAME == 'designgroup.oem.com') {
+    } else if (orgName == 'designgroup.oem.com') {
         client.loadFromConfig(DESGRP_CLIENT_CONNECTION_PROFILE_PATH)
     } else {
-        console.log("Invalid Org: ", ORG_NAME)
+        console.log("Invalid Org: ", orgName)
         process.exit(1)
     }
 
@@ -193,10 +302,10 @@ async function setupClient() {
 /**
  * Creates an instance of the Channel class
  */
-async function setupChannel() {
+async function setupChannel(client) {
     try {
         // Get the Channel class instance from client
-        channel = await client.getChannel(CHANNEL_NAME, true)
+        var channel = await client.getChannel(CHANNEL_NAME, true)
     } catch (e) {
         console.log("Could NOT create channel: ", CHANNEL_NAME)
         process.exit(1)
@@ -1,3 +1,4 @@
+const crypto = require('crypto')
 const fs = require('fs')
 const Client = require('fabric-client')
 
@@ -52,7 +53,8 @@ async function  invokeChaincode() {
         targets: peerName,
         chaincodeId: CHAINCODE_ID,
         fcn: 'NewAsset',
-        args: [reqID,"{\"firstname\": \"Rosesh\", \"lastname\": \"Sarabhai\"}",'200'],
+        args: [reqID,'{}'],
+        transientMap: {text: Buffer.from('200'), salt: Buffer.from(crypto.randomBytes(16).toString('hex'))},
         chainId: CHANNEL_NAME,
         txId: tx_id
     };
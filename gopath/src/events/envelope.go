@@ -0,0 +1,121 @@
+// Package events holds the envelope and payload types of the events raised by the oem, paramnet and simulation
+// chaincodes so listeners can decode them
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// SchemaVersion is the version of the envelope and the payload types in this package
+const SchemaVersion = 1
+
+// Asset types
+const (
+	AssetRequirement = "requirement"
+	AssetParameter   = "parameter"
+	AssetPackage     = "package"
+	AssetTestCase    = "testcase"
+	AssetRun         = "run"
+	AssetReport      = "report"
+)
+
+// Envelope wraps the payload of every chaincode event. Events about several assets leave AssetID empty and list
+// the assets in the body.
+type Envelope struct {
+	SchemaVersion int             `json:"schemaversion"`
+	EventType     string          `json:"eventtype"`
+	TxID          string          `json:"txid"`
+	Timestamp     string          `json:"timestamp"`
+	CreatorMSP    string          `json:"creatormsp"`
+	AssetType     string          `json:"assettype"`
+	AssetID       string          `json:"assetid"`
+	Body          json.RawMessage `json:"body"`
+}
+
+// UnknownEventError is returned when an event type has no payload type in this package
+type UnknownEventError struct {
+	EventType string
+}
+
+func (e *UnknownEventError) Error() string {
+	return fmt.Sprintf("Unknown event type %s", e.EventType)
+}
+
+// Marshal wraps body in an envelope and returns the event payload
+func Marshal(eventType string, txID string, timestamp string, creatorMSP string, assetType string, assetID string,
+	body interface{}) ([]byte, error) {
+
+	bodyBytes, err := json.Marshal(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Envelope{SchemaVersion: SchemaVersion, EventType: eventType, TxID: txID,
+		Timestamp: timestamp, CreatorMSP: creatorMSP, AssetType: assetType, AssetID: assetID, Body: bodyBytes})
+}
+
+// Raise wraps body in an envelope and emits it as the event of the transaction, Fabric keeps only the last event of
+// a transaction
+func Raise(ctx contractapi.TransactionContextInterface, eventType string, assetType string, assetID string,
+	body interface{}) error {
+
+	creatorMSP, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	// Every endorser sees the same transaction timestamp
+	ts, err := ctx.GetStub().GetTxTimestamp()
+
+	if err != nil {
+		return errors.New("Unable to read the transaction timestamp")
+	}
+
+	eventPayload, err := Marshal(eventType, ctx.GetStub().GetTxID(), strconv.FormatInt(ts.Seconds, 10), creatorMSP,
+		assetType, assetID, body)
+
+	if err != nil {
+		return errors.New("Unable to marshal event payload to JSON")
+	}
+
+	err = ctx.GetStub().SetEvent(eventType, eventPayload)
+
+	if err != nil {
+		return errors.New("Unable to raise event")
+	}
+
+	return nil
+}
+
+// Unmarshal decodes an event payload into its envelope and a pointer to the body type of its event type
+func Unmarshal(payload []byte) (*Envelope, interface{}, error) {
+
+	envelope := new(Envelope)
+	err := json.Unmarshal(payload, envelope)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newBody, ok := bodyTypes[envelope.EventType]
+
+	if !ok {
+		return envelope, nil, &UnknownEventError{EventType: envelope.EventType}
+	}
+
+	body := newBody()
+	err = json.Unmarshal(envelope.Body, body)
+
+	if err != nil {
+		return envelope, nil, err
+	}
+
+	return envelope, body, nil
+}
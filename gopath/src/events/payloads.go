@@ -0,0 +1,206 @@
+package events
+
+// Event types
+const (
+	NewAsset             = "newAsset"
+	AssetsCreated        = "assetsCreated"
+	AssetShared          = "assetShared"
+	AssetsShared         = "assetsShared"
+	AssetAccessed        = "assetAccessed"
+	AssetModified        = "assetModified"
+	StatusChanged        = "statusChanged"
+	AssetRetired         = "assetRetired"
+	AssetUnshared        = "assetUnshared"
+	RequirementApproved  = "requirementApproved"
+	RequirementRejected  = "requirementRejected"
+	CommentAdded         = "commentAdded"
+	ThreadResolved       = "threadResolved"
+	ChangeProposed       = "changeProposed"
+	ChangeObjected       = "changeObjected"
+	ObjectionResolved    = "objectionResolved"
+	ChangeRejected       = "changeRejected"
+	OwnershipOffered     = "ownershipOffered"
+	OwnershipTransferred = "ownershipTransferred"
+	OwnershipDeclined    = "ownershipDeclined"
+	ParameterCreated     = "parameterCreated"
+	PackageCreated       = "packageCreated"
+	TestCreated          = "testCreated"
+	RunCreated           = "runCreated"
+	ReportCreated        = "reportCreated"
+)
+
+// bodyTypes creates the body of each event type
+var bodyTypes = map[string]func() interface{}{
+	NewAsset:             func() interface{} { return new(NewAssetPayload) },
+	AssetsCreated:        func() interface{} { return new(BulkEventPayload) },
+	AssetShared:          func() interface{} { return new(ShareAssetPayload) },
+	AssetsShared:         func() interface{} { return new(BulkEventPayload) },
+	AssetAccessed:        func() interface{} { return new(ReadAssetPayload) },
+	AssetModified:        func() interface{} { return new(DepEventPayload) },
+	StatusChanged:        func() interface{} { return new(StatusChangePayload) },
+	AssetRetired:         func() interface{} { return new(ClosedPayload) },
+	AssetUnshared:        func() interface{} { return new(ClosedPayload) },
+	RequirementApproved:  func() interface{} { return new(ApprovalPayload) },
+	RequirementRejected:  func() interface{} { return new(ApprovalPayload) },
+	CommentAdded:         func() interface{} { return new(CommentPayload) },
+	ThreadResolved:       func() interface{} { return new(CommentPayload) },
+	ChangeProposed:       func() interface{} { return new(ChangePayload) },
+	ChangeObjected:       func() interface{} { return new(ChangePayload) },
+	ObjectionResolved:    func() interface{} { return new(ChangePayload) },
+	ChangeRejected:       func() interface{} { return new(ChangePayload) },
+	OwnershipOffered:     func() interface{} { return new(OwnershipPayload) },
+	OwnershipTransferred: func() interface{} { return new(OwnershipPayload) },
+	OwnershipDeclined:    func() interface{} { return new(OwnershipPayload) },
+	ParameterCreated:     func() interface{} { return new(ParameterCreatedPayload) },
+	PackageCreated:       func() interface{} { return new(NewPackageCreated) },
+	TestCreated:          func() interface{} { return new(TestCreatedPayload) },
+	RunCreated:           func() interface{} { return new(RunCreatedPayload) },
+	ReportCreated:        func() interface{} { return new(ReportCreatedPayload) },
+}
+
+// NewAssetPayload for event newAsset
+type NewAssetPayload struct {
+	CreateTime string `json:"createtime"`
+	Revision   int    `json:"revision"`
+	Status     string `json:"status"`
+}
+
+// BulkAssetEntry is one asset of a bulk event
+type BulkAssetEntry struct {
+	AssetID    string   `json:"assetid"`
+	Dependents []string `json:"dependents"`
+}
+
+// BulkEventPayload for events assetsCreated and assetsShared
+type BulkEventPayload struct {
+	Supplier string            `json:"supplier,omitempty"`
+	Assets   []*BulkAssetEntry `json:"assets"`
+}
+
+// ShareAssetPayload for event assetShared
+type ShareAssetPayload struct {
+	ShareTime  string   `json:"sharetime"`
+	Supplier   string   `json:"supplier"`
+	Dependents []string `json:"dependents"`
+}
+
+// ReadAssetPayload for event assetAccessed
+type ReadAssetPayload struct {
+	ShareTime string `json:"sharetime"`
+	ReadTime  string `json:"readtime"`
+	ReaderMSP string `json:"readermsp"`
+}
+
+// ImpactEntry is a requirement affected by a change, Depth 1 being a direct dependent
+type ImpactEntry struct {
+	ID       string `json:"id"`
+	Depth    int    `json:"depth"`
+	OwnerMSP string `json:"ownermsp"`
+}
+
+// DiffSummary counts the lines a revision adds, removes and changes, ChangedLines are line numbers in the newer
+// revision. It never carries the private text.
+type DiffSummary struct {
+	Added        int   `json:"added"`
+	Removed      int   `json:"removed"`
+	Changed      int   `json:"changed"`
+	ChangedLines []int `json:"changedlines"`
+}
+
+// DepEventPayload for event assetModified
+type DepEventPayload struct {
+	Revision    int            `json:"revision"`
+	ChangeID    string         `json:"changeid,omitempty"`
+	Changes     DiffSummary    `json:"changes"`
+	Dependents  []string       `json:"dependents"`
+	Impact      []*ImpactEntry `json:"impact"`
+	PendingAcks []string       `json:"pendingacks"`
+}
+
+// StatusChangePayload for event statusChanged
+type StatusChangePayload struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ClosedPayload for events assetRetired and assetUnshared
+type ClosedPayload struct {
+	Status    string         `json:"status"`
+	Reason    string         `json:"reason"`
+	Suppliers []string       `json:"suppliers"`
+	Affected  []*ImpactEntry `json:"affected"`
+}
+
+// Signature is one organization's decision on an approval request
+type Signature struct {
+	MSPID    string `json:"mspid"`
+	Identity string `json:"identity"`
+	Decision string `json:"decision"`
+	Comment  string `json:"comment"`
+	Time     string `json:"time"`
+	TxID     string `json:"txid"`
+}
+
+// ApprovalPayload for events requirementApproved and requirementRejected
+type ApprovalPayload struct {
+	Revision   int          `json:"revision"`
+	Signatures []*Signature `json:"signatures"`
+}
+
+// CommentPayload for events commentAdded and threadResolved, the comment text stays in the private collections
+type CommentPayload struct {
+	CommentID string `json:"commentid"`
+	ParentID  string `json:"parentid"`
+	ThreadID  string `json:"threadid"`
+	AuthorMSP string `json:"authormsp"`
+}
+
+// ChangePayload for events changeProposed, changeObjected, objectionResolved and changeRejected, Changes summarizes
+// the lines the proposed text changes. An accepted change raises assetModified with its ChangeID instead.
+type ChangePayload struct {
+	ChangeID     string         `json:"changeid"`
+	BaseRevision int            `json:"baserevision"`
+	Status       string         `json:"status"`
+	Rationale    string         `json:"rationale"`
+	Changes      DiffSummary    `json:"changes"`
+	Comment      string         `json:"comment"`
+	Dependents   []string       `json:"dependents"`
+	Impact       []*ImpactEntry `json:"impact"`
+}
+
+// OwnershipPayload for events ownershipOffered, ownershipTransferred and ownershipDeclined
+type OwnershipPayload struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ParameterCreatedPayload for event parameterCreated
+type ParameterCreatedPayload struct {
+	Name      string  `json:"name"`
+	MinValue  float32 `json:"min"`
+	MaxValue  float32 `json:"max"`
+	GoalValue float32 `json:"goal"`
+}
+
+// NewPackageCreated for event packageCreated
+type NewPackageCreated struct {
+	Parameters []string `json:"parameters"`
+}
+
+// TestCreatedPayload for event testCreated
+type TestCreatedPayload struct {
+	ParamID string `json:"paramid"`
+	Result  string `json:"result"`
+}
+
+// RunCreatedPayload for event runCreated
+type RunCreatedPayload struct {
+	TestCases []string `json:"testcases"`
+	Result    string   `json:"result"`
+}
+
+// ReportCreatedPayload for event reportCreated
+type ReportCreatedPayload struct {
+	Runs       []string `json:"runs"`
+	Acceptable bool     `json:"acceptable"`
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Acknowledgement states, a pending acknowledgement is superseded by the one opened for a later revision and
+// cancelled when the requirement is retired or withdrawn
+const (
+	ackPending      = "pending"
+	ackAcknowledged = "acknowledged"
+	ackSuperseded   = "superseded"
+	ackCancelled    = "cancelled"
+)
+
+// Acknowledgement tracks whether an organization owning affected requirements reviewed a revision
+type Acknowledgement struct {
+	ReqID          string   `json:"reqid"`
+	Revision       int      `json:"revision"`
+	MSPID          string   `json:"mspid"`
+	Affected       []string `json:"affected"`
+	Status         string   `json:"status"`
+	OpenTime       string   `json:"opentime"`
+	AcknowledgedBy string   `json:"acknowledgedby"`
+	AckTime        string   `json:"acktime"`
+	Comment        string   `json:"comment"`
+	CloseTime      string   `json:"closetime"`
+	CloseReason    string   `json:"closereason"`
+}
+
+// AcknowledgeChange closes the pending acknowledgement of the caller's organization for a revision
+func (cc *OEMContract) AcknowledgeChange(ctx contractapi.TransactionContextInterface, reqID string, revision int,
+	comment string) (*Acknowledgement, error) {
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(ackObjectType, []string{mspID, reqID, strconv.Itoa(revision)})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("%s has no acknowledgement open for revision %d of asset %s", mspID, revision, reqID)
+	}
+
+	ack := new(Acknowledgement)
+	err = json.Unmarshal(existing, ack)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Acknowledgement", key)
+	}
+
+	if ack.Status != ackPending {
+		return nil, fmt.Errorf("The acknowledgement of %s for revision %d of asset %s is %s", mspID, revision, reqID,
+			ack.Status)
+	}
+
+	ack.AcknowledgedBy, err = ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	ack.AckTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ack.Status = ackAcknowledged
+	ack.Comment = comment
+
+	ackBytes, _ := json.Marshal(ack)
+	err = ctx.GetStub().PutState(key, ackBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the acknowledgement to the world state")
+	}
+
+	return ack, nil
+}
+
+// GetOutstandingAcknowledgements returns the acknowledgements the organization has not closed yet
+func (cc *OEMContract) GetOutstandingAcknowledgements(ctx contractapi.TransactionContextInterface,
+	mspID string) ([]*Acknowledgement, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(ackObjectType, []string{mspID})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	outstanding := []*Acknowledgement{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read acknowledgements from the world state")
+		}
+
+		ack := new(Acknowledgement)
+		err = json.Unmarshal(kv.Value, ack)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Acknowledgement", kv.Key)
+		}
+
+		if ack.Status == ackPending {
+			outstanding = append(outstanding, ack)
+		}
+	}
+
+	return outstanding, nil
+}
+
+// openAcknowledgements opens one pending acknowledgement per organization owning requirements in the impact set,
+// the organization making the change excluded, and supersedes the ones they left open for earlier revisions. It
+// returns the organizations in a stable order.
+func openAcknowledgements(ctx contractapi.TransactionContextInterface, req *Requirement,
+	impact []*ImpactEntry) ([]string, error) {
+
+	affected := map[string][]string{}
+
+	for _, entry := range impact {
+		if entry.Owner.MSPID != req.Owner.MSPID {
+			affected[entry.Owner.MSPID] = append(affected[entry.Owner.MSPID], entry.ID)
+		}
+	}
+
+	// Map order is random, endorsers must write in the same order
+	orgs := []string{}
+
+	for mspID := range affected {
+		orgs = append(orgs, mspID)
+	}
+
+	sort.Strings(orgs)
+
+	openTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = closeAcknowledgements(ctx, req.ID, orgs, ackSuperseded,
+		fmt.Sprintf("Superseded by revision %d", req.Revision))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mspID := range orgs {
+		ack := Acknowledgement{ReqID: req.ID, Revision: req.Revision, MSPID: mspID, Affected: affected[mspID],
+			Status: ackPending, OpenTime: openTime}
+
+		key, _ := ctx.GetStub().CreateCompositeKey(ackObjectType, []string{mspID, req.ID, strconv.Itoa(req.Revision)})
+		ackBytes, _ := json.Marshal(ack)
+
+		if ctx.GetStub().PutState(key, ackBytes) != nil {
+			return nil, errors.New("Unable to commit the acknowledgement to the world state")
+		}
+	}
+
+	return orgs, nil
+}
+
+// closeAcknowledgements moves the pending acknowledgements the organizations hold for a requirement to status
+func closeAcknowledgements(ctx contractapi.TransactionContextInterface, reqID string, orgs []string, status string,
+	reason string) error {
+
+	closeTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, mspID := range orgs {
+		iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(ackObjectType, []string{mspID, reqID})
+
+		if err != nil {
+			return errors.New("Unable to interact with the world state")
+		}
+
+		pending := []*Acknowledgement{}
+
+		for iterator.HasNext() {
+			kv, err := iterator.Next()
+
+			if err != nil {
+				iterator.Close()
+				return errors.New("Unable to read acknowledgements from the world state")
+			}
+
+			ack := new(Acknowledgement)
+			err = json.Unmarshal(kv.Value, ack)
+
+			if err != nil {
+				iterator.Close()
+				return fmt.Errorf("Data retrieved from world state for key %s was not of type Acknowledgement",
+					kv.Key)
+			}
+
+			if ack.Status == ackPending {
+				pending = append(pending, ack)
+			}
+		}
+
+		iterator.Close()
+
+		for _, ack := range pending {
+			ack.Status = status
+			ack.CloseTime = closeTime
+			ack.CloseReason = reason
+
+			key, _ := ctx.GetStub().CreateCompositeKey(ackObjectType, []string{mspID, reqID,
+				strconv.Itoa(ack.Revision)})
+			ackBytes, _ := json.Marshal(ack)
+
+			if ctx.GetStub().PutState(key, ackBytes) != nil {
+				return errors.New("Unable to commit the acknowledgement to the world state")
+			}
+		}
+	}
+
+	return nil
+}
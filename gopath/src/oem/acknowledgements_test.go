@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// outstanding returns the acknowledgements mspID has not closed yet
+func (n *testNetwork) outstanding(mspID string) []*Acknowledgement {
+	n.t.Helper()
+
+	var acks []*Acknowledgement
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		acks, err = n.cc.GetOutstandingAcknowledgements(ctx, mspID)
+		return err
+	})
+
+	return acks
+}
+
+// revise accepts text as the next revision of requirement id of mspID
+func (n *testNetwork) revise(mspID string, id string, text string) {
+	n.t.Helper()
+
+	n.accept(mspID, id, n.propose(mspID, id, text))
+}
+
+// acknowledge is the transaction acknowledging revision rev of requirement id
+func (n *testNetwork) acknowledge(id string, rev int) txFunc {
+	return func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcknowledgeChange(ctx, id, rev, "Reviewed, no impact on the disc")
+		return err
+	}
+}
+
+func TestRevisionOpensAndAcknowledgementCloses(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "DES-1", "REQ-1")
+	})
+
+	n.revise(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 36 m")
+
+	_, body := n.lastEvent()
+
+	if pending := body.(*events.DepEventPayload).PendingAcks; len(pending) != 1 || pending[0] != orgDesign {
+		t.Fatalf("the event does not list the pending acknowledgements: %v", pending)
+	}
+
+	acks := n.outstanding(orgDesign)
+
+	if len(acks) != 1 || acks[0].ReqID != "REQ-1" || acks[0].Revision != 2 || acks[0].Affected[0] != "DES-1" {
+		t.Fatalf("expected one acknowledgement for revision 2, got %+v", acks)
+	}
+
+	// The owner making the change has nothing to acknowledge, only the affected organization closes its own
+	if len(n.outstanding(orgRequirements)) != 0 {
+		t.Fatal("the owner was asked to acknowledge its own change")
+	}
+
+	n.mustFail(orgSupplier, nil, n.acknowledge("REQ-1", 2))
+	n.mustSubmit(orgDesign, nil, n.acknowledge("REQ-1", 2))
+	n.mustFail(orgDesign, nil, n.acknowledge("REQ-1", 2))
+
+	if acks := n.outstanding(orgDesign); len(acks) != 0 {
+		t.Fatalf("the acknowledgement stays outstanding: %+v", acks)
+	}
+}
+
+func TestLaterRevisionSupersedesAcknowledgement(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "DES-1", "REQ-1")
+	})
+
+	for rev := 2; rev <= 4; rev++ {
+		n.revise(orgRequirements, "REQ-1", fmt.Sprintf("The vehicle shall stop from 100 km/h within %d m", 40-rev))
+	}
+
+	// Only the latest revision needs a review
+	if acks := n.outstanding(orgDesign); len(acks) != 1 || acks[0].Revision != 4 {
+		t.Fatalf("expected the acknowledgement of revision 4 only, got %+v", acks)
+	}
+
+	n.mustFail(orgDesign, nil, n.acknowledge("REQ-1", 3))
+
+	// Retiring the requirement cancels what is still open
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RetireRequirement(ctx, "REQ-1", "Superseded by the new braking regulation")
+		return err
+	})
+
+	if acks := n.outstanding(orgDesign); len(acks) != 0 {
+		t.Fatalf("the acknowledgement of a retired requirement stays outstanding: %+v", acks)
+	}
+
+	n.mustFail(orgDesign, nil, n.acknowledge("REQ-1", 4))
+}
+
+func TestUnshareCancelsAcknowledgements(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "DES-1", "REQ-1")
+	})
+
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.revise(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 36 m")
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.UnshareAsset(ctx, "REQ-1", "Replaced by REQ-2")
+		return err
+	})
+
+	if acks := n.outstanding(orgDesign); len(acks) != 0 {
+		t.Fatalf("the acknowledgement of a withdrawn requirement stays outstanding: %+v", acks)
+	}
+}
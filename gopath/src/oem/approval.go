@@ -0,0 +1,344 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Approval request states and signature decisions
+const (
+	approvalPending  = "pending"
+	approvalApproved = "approved"
+	approvalRejected = "rejected"
+)
+
+// RequestApproval puts the current revision of a requirement in review and asks the approver organizations to sign
+// it off, the requirement is approved once quorum of them approve. A quorum of 0 requires every approver. The owner
+// cannot approve its own requirement, and a pending request on the current revision keeps its signatures until it
+// is settled. The revision under review is copied into the collection of the owner and each approver so the
+// approvers can read what they sign off. A shared requirement revised since its approval stays shared while its new
+// revision is reviewed.
+func (cc *OEMContract) RequestApproval(ctx contractapi.TransactionContextInterface, id string,
+	approverMSPs []string, quorum int) (*ApprovalRequest, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(approverMSPs) == 0 {
+		return nil, errors.New("At least one approver organization is required")
+	}
+
+	for i, approverMSP := range approverMSPs {
+		if approverMSP == req.Owner.MSPID {
+			return nil, fmt.Errorf("%s owns asset %s and cannot approve it", approverMSP, id)
+		}
+
+		if contains(approverMSPs[:i], approverMSP) {
+			return nil, fmt.Errorf("%s is listed as approver more than once", approverMSP)
+		}
+	}
+
+	if quorum == 0 {
+		quorum = len(approverMSPs)
+	}
+
+	if quorum < 0 || quorum > len(approverMSPs) {
+		return nil, fmt.Errorf("Quorum must be between 1 and %d", len(approverMSPs))
+	}
+
+	// A parent cannot be approved while its children are drafts, so do not ask for it
+	err = cc.checkChildStatus(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == StatusShared && req.ApprovedRevision == req.Revision {
+		return nil, fmt.Errorf("Revision %d of asset %s is already approved", req.Revision, id)
+	}
+
+	// Signatures already collected on the current revision are not thrown away
+	if req.Status == StatusInReview || req.Status == StatusShared {
+		key, _ := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{id})
+		existing, err := ctx.GetStub().GetState(key)
+
+		if err != nil {
+			return nil, errors.New("Unable to interact with the world state")
+		}
+
+		current := new(ApprovalRequest)
+
+		if existing != nil && json.Unmarshal(existing, current) == nil && current.Status == approvalPending &&
+			current.Revision == req.Revision {
+			return nil, fmt.Errorf("Approval of asset %s is already pending", id)
+		}
+	}
+
+	// A draft goes into review, a requirement already in review gets a fresh request
+	if req.Status != StatusInReview && req.Status != StatusShared {
+		err = req.transitionTo(StatusInReview)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reqBytes, _ := json.Marshal(req)
+		err = putRequirementState(ctx, id, reqBytes)
+
+		if err != nil {
+			return nil, errors.New("Unable to update the world state")
+		}
+	}
+
+	requestTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &ApprovalRequest{ReqID: id, Revision: req.Revision, Approvers: approverMSPs, Quorum: quorum,
+		Signatures: []*Signature{}, Status: approvalPending, RequestTime: requestTime, TxID: ctx.GetStub().GetTxID()}
+
+	err = putApproval(ctx, approval)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := getContentText(ctx, privateCollection(req.Owner.MSPID), req.ContentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, approverMSP := range approverMSPs {
+		err = putContentText(ctx, sharedCollection(req.Owner.MSPID, approverMSP), text)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return approval, nil
+}
+
+// Approve signs off the pending approval request of a requirement for the caller's organization
+func (cc *OEMContract) Approve(ctx contractapi.TransactionContextInterface, id string,
+	comment string) (*ApprovalRequest, error) {
+
+	return cc.sign(ctx, id, approvalApproved, comment)
+}
+
+// Reject refuses the pending approval request of a requirement and sends the requirement back to draft
+func (cc *OEMContract) Reject(ctx contractapi.TransactionContextInterface, id string,
+	comment string) (*ApprovalRequest, error) {
+
+	return cc.sign(ctx, id, approvalRejected, comment)
+}
+
+// GetApproval returns the latest approval request of a requirement
+func (cc *OEMContract) GetApproval(ctx contractapi.TransactionContextInterface, id string) (*ApprovalRequest, error) {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{id})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("No approval was requested for asset with id %s", id)
+	}
+
+	approval := new(ApprovalRequest)
+	err = json.Unmarshal(existing, approval)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type ApprovalRequest", key)
+	}
+
+	return approval, nil
+}
+
+// authorizeApprover fails unless the caller's organization is asked to sign off revision rev of the requirement by a
+// pending approval request
+func (cc *OEMContract) authorizeApprover(ctx contractapi.TransactionContextInterface, req *Requirement,
+	rev int) (string, error) {
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return "", errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	approval, err := cc.GetApproval(ctx, req.ID)
+
+	if err != nil || approval.Status != approvalPending || approval.Revision != rev ||
+		!contains(approval.Approvers, mspID) {
+		return "", &AccessError{ID: req.ID, CallerMSP: mspID}
+	}
+
+	return mspID, nil
+}
+
+// sign records the caller's decision and settles the request once it is rejected or reaches quorum
+func (cc *OEMContract) sign(ctx contractapi.TransactionContextInterface, id string, decision string,
+	comment string) (*ApprovalRequest, error) {
+
+	approval, err := cc.GetApproval(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Status != approvalPending {
+		return nil, fmt.Errorf("The approval request of asset %s is already %s", id, approval.Status)
+	}
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Signatures only count for the text they were requested on
+	if (req.Status != StatusInReview && req.Status != StatusShared) || req.Revision != approval.Revision {
+		return nil, fmt.Errorf("Asset %s changed since approval was requested, approval must be requested again", id)
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	if !contains(approval.Approvers, mspID) {
+		return nil, &AuthorizationError{ID: id, CallerMSP: mspID, OwnerMSP: req.Owner.MSPID}
+	}
+
+	approved := 0
+
+	for _, signature := range approval.Signatures {
+		if signature.MSPID == mspID {
+			return nil, fmt.Errorf("%s already signed the approval request of asset %s", mspID, id)
+		}
+
+		if signature.Decision == approvalApproved {
+			approved++
+		}
+	}
+
+	identity, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	signTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	approval.Signatures = append(approval.Signatures, &Signature{MSPID: mspID, Identity: identity,
+		Decision: decision, Comment: comment, Time: signTime, TxID: ctx.GetStub().GetTxID()})
+
+	// A single rejection ends the request, enough approvals approve the requirement. A shared requirement stays
+	// shared either way.
+	eventName := ""
+
+	if decision == approvalRejected {
+		approval.Status = approvalRejected
+		eventName = events.RequirementRejected
+
+		if req.Status != StatusShared {
+			err = req.transitionTo(StatusDraft)
+		}
+	} else if approved+1 >= approval.Quorum {
+		// Children may have gone back to draft since approval was requested
+		err = cc.checkChildStatus(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		approval.Status = approvalApproved
+		eventName = events.RequirementApproved
+		req.ApprovedRevision = approval.Revision
+
+		if req.Status != StatusShared {
+			err = req.transitionTo(StatusApproved)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = putApproval(ctx, approval)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if eventName == "" {
+		return approval, nil
+	}
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, id, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, eventName, id, events.ApprovalPayload{Revision: approval.Revision,
+		Signatures: signaturesPayload(approval.Signatures)})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+// putApproval stores the approval request of a requirement, replacing any earlier one
+func putApproval(ctx contractapi.TransactionContextInterface, approval *ApprovalRequest) error {
+	key, _ := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{approval.ReqID})
+	approvalBytes, _ := json.Marshal(approval)
+
+	err := ctx.GetStub().PutState(key, approvalBytes)
+
+	if err != nil {
+		return errors.New("Unable to commit the approval request to the world state")
+	}
+
+	return nil
+}
+
+// contains reports whether value is in list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
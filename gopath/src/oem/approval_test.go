@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestOwnerCannotApproveOwnRequirement(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	for _, approvers := range [][]string{{orgRequirements}, {orgDesign, orgRequirements}, {orgDesign, orgDesign}} {
+		n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.RequestApproval(ctx, "REQ-1", approvers, 1)
+			return err
+		})
+	}
+
+	if status := n.requirement("REQ-1").Status; status != StatusDraft {
+		t.Fatalf("a refused request moved the requirement to %s", status)
+	}
+}
+
+func TestPendingApprovalKeepsSignatures(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RequestApproval(ctx, "REQ-1", []string{orgDesign, orgSimulation}, 0)
+		return err
+	})
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.Approve(ctx, "REQ-1", "")
+		return err
+	})
+
+	// Asking again would discard the approval of Design
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RequestApproval(ctx, "REQ-1", []string{orgSimulation}, 0)
+		return err
+	})
+
+	var approval *ApprovalRequest
+
+	n.mustSubmit(orgSimulation, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		approval, err = n.cc.Approve(ctx, "REQ-1", "")
+		return err
+	})
+
+	if approval.Status != approvalApproved || len(approval.Signatures) != 2 {
+		t.Fatalf("expected the request to be approved by both organizations, got %+v", approval)
+	}
+
+	if status := n.requirement("REQ-1").Status; status != StatusApproved {
+		t.Fatalf("expected REQ-1 to be approved, got %s", status)
+	}
+}
+
+func TestApproverReadsTextBeforeSigning(t *testing.T) {
+	n := newTestNetwork(t)
+	text := "The vehicle shall stop from 100 km/h within 40 m"
+	n.create(orgRequirements, "REQ-1", text)
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RequestApproval(ctx, "REQ-1", []string{orgDesign}, 0)
+		return err
+	})
+
+	read := func(ctx contractapi.TransactionContextInterface) error {
+		private, err := n.cc.ReadContentText(ctx, "REQ-1", 1)
+
+		if err == nil && private.Text != text {
+			t.Fatalf("the approver read %q", private.Text)
+		}
+
+		return err
+	}
+
+	n.mustSubmit(orgDesign, nil, read)
+
+	// Organizations that are not asked to approve still cannot read
+	err := n.mustFail(orgSupplier, nil, read)
+
+	if _, ok := err.(*AccessError); !ok {
+		t.Fatalf("expected an AccessError, got %v", err)
+	}
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.Approve(ctx, "REQ-1", "")
+		return err
+	})
+
+	// Once the request is settled only sharing gives access
+	n.mustFail(orgDesign, nil, read)
+}
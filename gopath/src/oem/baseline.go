@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// BaselineEntry is the state of one requirement when a baseline was taken
+type BaselineEntry struct {
+	ReqID       string   `json:"reqid"`
+	Revision    int      `json:"revision"`
+	ContentHash string   `json:"contenthash"`
+	Status      string   `json:"status"`
+	DependsOn   []string `json:"dependson"`
+}
+
+// Baseline freezes a set of requirements, Digest covers the entries and lets parties compare baselines off chain.
+// Filter is the query the requirements were selected by, if they were not listed.
+type Baseline struct {
+	ID         string            `json:"id"`
+	Filter     RequirementFilter `json:"filter"`
+	Entries    []*BaselineEntry  `json:"entries"`
+	Digest     string            `json:"digest"`
+	CreatorMSP string            `json:"creatormsp"`
+	CreatedBy  string            `json:"createdby"`
+	CreateTime string            `json:"createtime"`
+	TxID       string            `json:"txid"`
+}
+
+// BaselineChange is a requirement found in both baselines with a different revision, status or dependencies
+type BaselineChange struct {
+	ReqID  string         `json:"reqid"`
+	Fields []string       `json:"fields"`
+	From   *BaselineEntry `json:"from"`
+	To     *BaselineEntry `json:"to"`
+}
+
+// BaselineComparison lists what changed from one baseline to another
+type BaselineComparison struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Added   []string          `json:"added"`
+	Removed []string          `json:"removed"`
+	Changed []*BaselineChange `json:"changed"`
+}
+
+// CreateBaseline records the current revision, status and dependencies of the listed requirements, or of the ones
+// matching the filter. Rich query results are not re-checked when a transaction commits, so the baseline keeps the
+// filter next to the requirements it matched on the endorsing peers. Baselines can never be changed.
+func (cc *OEMContract) CreateBaseline(ctx contractapi.TransactionContextInterface, baselineID string,
+	reqIDs []string, filter RequirementFilter) (*Baseline, error) {
+
+	if len(reqIDs) == 0 && filter == (RequirementFilter{}) {
+		return nil, errors.New("Either requirement ids or a filter is required")
+	}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(baselineObjectType, []string{baselineID})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing != nil {
+		return nil, fmt.Errorf("Baseline with id %s already exists", baselineID)
+	}
+
+	reqs, err := cc.selectRequirements(ctx, reqIDs, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the matches again by key, a requirement changing before the baseline commits then fails validation
+	if len(reqIDs) == 0 {
+		matched := []string{}
+
+		for _, req := range reqs {
+			matched = append(matched, req.ID)
+		}
+
+		reqs, err = cc.loadRequirements(ctx, matched)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(reqs) == 0 {
+		return nil, errors.New("A baseline needs at least one requirement")
+	}
+
+	entries := []*BaselineEntry{}
+
+	for _, req := range reqs {
+		content, err := cc.GetContentRevision(ctx, req.ID, req.Revision)
+
+		if err != nil {
+			return nil, err
+		}
+
+		dependsOn, err := listEdges(ctx, dependencyObjectType, req.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &BaselineEntry{ReqID: req.ID, Revision: req.Revision, ContentHash: content.Hash,
+			Status: req.Status, DependsOn: dependsOn})
+	}
+
+	// Sorted entries give the same digest for the same set of requirements
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ReqID < entries[j].ReqID })
+
+	creatorMSP, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	createdBy, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	createTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entryBytes, _ := json.Marshal(entries)
+	sum := sha256.Sum256(entryBytes)
+
+	baseline := &Baseline{ID: baselineID, Filter: filter, Entries: entries, Digest: hex.EncodeToString(sum[:]),
+		CreatorMSP: creatorMSP, CreatedBy: createdBy, CreateTime: createTime, TxID: ctx.GetStub().GetTxID()}
+
+	baselineBytes, _ := json.Marshal(baseline)
+	err = ctx.GetStub().PutState(key, baselineBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the baseline to the world state")
+	}
+
+	return baseline, nil
+}
+
+// GetBaseline returns the baseline with id given from the world state
+func (cc *OEMContract) GetBaseline(ctx contractapi.TransactionContextInterface, id string) (*Baseline, error) {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(baselineObjectType, []string{id})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Baseline with id %s does not exist", id)
+	}
+
+	baseline := new(Baseline)
+	err = json.Unmarshal(existing, baseline)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Baseline", key)
+	}
+
+	return baseline, nil
+}
+
+// CompareBaselines reports the requirements added to, removed from and changed between baseline a and baseline b
+func (cc *OEMContract) CompareBaselines(ctx contractapi.TransactionContextInterface, a string,
+	b string) (*BaselineComparison, error) {
+
+	from, err := cc.GetBaseline(ctx, a)
+
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := cc.GetBaseline(ctx, b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &BaselineComparison{From: a, To: b, Added: []string{}, Removed: []string{},
+		Changed: []*BaselineChange{}}
+
+	fromEntries := map[string]*BaselineEntry{}
+
+	for _, entry := range from.Entries {
+		fromEntries[entry.ReqID] = entry
+	}
+
+	// Entries are sorted by requirement id, so the lists come out sorted as well
+	for _, entry := range to.Entries {
+		old, ok := fromEntries[entry.ReqID]
+
+		if !ok {
+			comparison.Added = append(comparison.Added, entry.ReqID)
+			continue
+		}
+
+		delete(fromEntries, entry.ReqID)
+
+		fields := []string{}
+
+		if old.Revision != entry.Revision || old.ContentHash != entry.ContentHash {
+			fields = append(fields, "content")
+		}
+
+		if old.Status != entry.Status {
+			fields = append(fields, "status")
+		}
+
+		if !sameEdges(old.DependsOn, entry.DependsOn) {
+			fields = append(fields, "dependencies")
+		}
+
+		if len(fields) > 0 {
+			comparison.Changed = append(comparison.Changed, &BaselineChange{ReqID: entry.ReqID, Fields: fields,
+				From: old, To: entry})
+		}
+	}
+
+	for _, entry := range from.Entries {
+		if _, ok := fromEntries[entry.ReqID]; ok {
+			comparison.Removed = append(comparison.Removed, entry.ReqID)
+		}
+	}
+
+	return comparison, nil
+}
+
+// loadRequirements loads the listed requirements once each. Every requirement is read by key, so the baseline fails
+// validation if one of them changes before it commits.
+func (cc *OEMContract) loadRequirements(ctx contractapi.TransactionContextInterface,
+	reqIDs []string) ([]*Requirement, error) {
+
+	reqs := []*Requirement{}
+	seen := map[string]bool{}
+
+	for _, id := range reqIDs {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		req, err := cc.GetAsset(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	return reqs, nil
+}
+
+// sameEdges reports whether both sorted edge lists are equal
+func sameEdges(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// baseline takes baseline id over reqIDs as the Requirements organization
+func (n *testNetwork) baseline(id string, reqIDs ...string) *Baseline {
+	n.t.Helper()
+
+	var baseline *Baseline
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		baseline, err = n.cc.CreateBaseline(ctx, id, reqIDs, RequirementFilter{})
+		return err
+	})
+
+	return baseline
+}
+
+func TestBaselinesRecordListedRequirements(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	n.create(orgRequirements, "REQ-3", "The brake disc diameter shall be 330 mm")
+
+	for _, reqIDs := range [][]string{{}, {"REQ-1", "REQ-4"}} {
+		n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.CreateBaseline(ctx, "BL-1", reqIDs, RequirementFilter{})
+			return err
+		})
+	}
+
+	first := n.baseline("BL-1", "REQ-2", "REQ-1", "REQ-2")
+
+	if len(first.Entries) != 2 || first.Entries[0].ReqID != "REQ-1" || first.Entries[1].ReqID != "REQ-2" {
+		t.Fatalf("expected sorted entries for REQ-1 and REQ-2, got %+v", first.Entries)
+	}
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "REQ-2", "REQ-1")
+	})
+
+	n.baseline("BL-2", "REQ-2", "REQ-3")
+
+	// Baselines can never be changed
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.CreateBaseline(ctx, "BL-1", []string{"REQ-3"}, RequirementFilter{})
+		return err
+	})
+
+	var comparison *BaselineComparison
+
+	n.mustSubmit(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		comparison, err = n.cc.CompareBaselines(ctx, "BL-1", "BL-2")
+		return err
+	})
+
+	if !reflect.DeepEqual(comparison.Added, []string{"REQ-3"}) ||
+		!reflect.DeepEqual(comparison.Removed, []string{"REQ-1"}) ||
+		len(comparison.Changed) != 1 || !reflect.DeepEqual(comparison.Changed[0].Fields, []string{"dependencies"}) {
+		t.Fatalf("unexpected comparison %+v", comparison)
+	}
+}
+
+func TestBaselineFromFilterRecordsMatches(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	filter := RequirementFilter{OwnerMSP: orgRequirements}
+
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.CreateBaseline(ctx, "BL-1", []string{"DES-1"}, filter)
+		return err
+	})
+
+	var baseline *Baseline
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		baseline, err = n.cc.CreateBaseline(ctx, "BL-1", nil, filter)
+		return err
+	})
+
+	if len(baseline.Entries) != 2 || baseline.Entries[0].ReqID != "REQ-1" || baseline.Entries[1].ReqID != "REQ-2" {
+		t.Fatalf("expected entries for the requirements of %s, got %+v", orgRequirements, baseline.Entries)
+	}
+
+	if baseline.Filter != filter {
+		t.Fatalf("the baseline does not record its filter: %+v", baseline.Filter)
+	}
+}
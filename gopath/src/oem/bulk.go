@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// NewAssetInput is one requirement of a bulk creation
+type NewAssetInput struct {
+	ID         string     `json:"id"`
+	Attributes Attributes `json:"attributes" metadata:"attributes,optional"`
+}
+
+// NewAssetsBulk creates requirements in BULK, the texts are read from the transient map as a JSON object keyed by
+// asset id and each text is salted with a value derived from the transaction salt. Nothing is written unless every
+// requirement can be created.
+func (cc *OEMContract) NewAssetsBulk(ctx contractapi.TransactionContextInterface, input []NewAssetInput) error {
+
+	if len(input) == 0 {
+		return errors.New("At least one asset is required")
+	}
+
+	texts, salt, err := transientTexts(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// Validate every item first, the world state does not show this transaction's own writes
+	seen := map[string]bool{}
+
+	for _, item := range input {
+		if item.ID == "" {
+			return errors.New("Every asset needs an id")
+		}
+
+		if seen[item.ID] {
+			return fmt.Errorf("Asset with id %s is listed more than once", item.ID)
+		}
+
+		seen[item.ID] = true
+
+		if _, ok := texts[item.ID]; !ok {
+			return fmt.Errorf("No text was passed for asset with id %s", item.ID)
+		}
+
+		err = cc.checkNewAsset(ctx, item.ID, item.Attributes)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	payload := events.BulkEventPayload{Assets: []*events.BulkAssetEntry{}}
+
+	for _, item := range input {
+		ba, err := cc.createAsset(ctx, item.ID, item.Attributes, texts[item.ID], itemSalt(salt, item.ID))
+
+		if err != nil {
+			return err
+		}
+
+		// A new asset has no dependents yet
+		payload.Assets = append(payload.Assets, &events.BulkAssetEntry{AssetID: ba.ID, Dependents: []string{}})
+	}
+
+	// One event for the whole batch, Fabric keeps only the last event of a transaction
+	return raiseEvent(ctx, events.AssetsCreated, "", payload)
+}
+
+// ShareAssetsBulk shares requirements in BULK with a supplier, the texts of their current revisions are passed in the
+// transient map. Nothing is written unless every requirement can be shared.
+func (cc *OEMContract) ShareAssetsBulk(ctx contractapi.TransactionContextInterface, input []string,
+	supplierMSP string) error {
+
+	if len(input) == 0 {
+		return errors.New("At least one asset is required")
+	}
+
+	contents, err := transientContents(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// Validate every item first, the world state does not show this transaction's own writes
+	reqs := []*Requirement{}
+	texts := []*ContentText{}
+	seen := map[string]bool{}
+
+	for _, id := range input {
+		if seen[id] {
+			return fmt.Errorf("Asset with id %s is listed more than once", id)
+		}
+
+		seen[id] = true
+
+		req, text, err := cc.checkShare(ctx, id, supplierMSP, contents)
+
+		if err != nil {
+			return err
+		}
+
+		reqs = append(reqs, req)
+		texts = append(texts, text)
+	}
+
+	payload := events.BulkEventPayload{Supplier: supplierMSP, Assets: []*events.BulkAssetEntry{}}
+
+	for i, req := range reqs {
+		err := shareRequirement(ctx, req, texts[i], supplierMSP)
+
+		if err != nil {
+			return err
+		}
+
+		depIDs, err := cc.GetDependents(ctx, req.ID)
+
+		if err != nil {
+			return err
+		}
+
+		payload.Assets = append(payload.Assets, &events.BulkAssetEntry{AssetID: req.ID, Dependents: depIDs})
+	}
+
+	// One event for the whole batch, Fabric keeps only the last event of a transaction
+	return raiseEvent(ctx, events.AssetsShared, "", payload)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// withTexts is the transient map carrying the texts of a bulk creation keyed by asset id
+func withTexts(texts map[string]string) map[string]string {
+	textsBytes, _ := json.Marshal(texts)
+
+	return map[string]string{transientTextsKey: string(textsBytes), transientSaltKey: testSalt}
+}
+
+func TestNewAssetsBulkIsAllOrNothing(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+
+	texts := withTexts(map[string]string{"REQ-1": "The vehicle shall stop from 100 km/h within 40 m",
+		"REQ-2": "The brake pedal force shall not exceed 450 N", "REQ-3": "The brake disc diameter shall be 330 mm"})
+
+	// REQ-2 exists already, neither of the others may be created
+	n.mustFail(orgRequirements, texts, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.NewAssetsBulk(ctx, []NewAssetInput{{ID: "REQ-1"}, {ID: "REQ-2"}, {ID: "REQ-3"}})
+	})
+
+	// A missing text fails the whole batch as well
+	n.mustFail(orgRequirements, texts, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.NewAssetsBulk(ctx, []NewAssetInput{{ID: "REQ-1"}, {ID: "REQ-4"}})
+	})
+
+	for _, id := range []string{"REQ-1", "REQ-3", "REQ-4"} {
+		n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			existing, err := getRequirementState(ctx, id)
+
+			if existing != nil {
+				t.Fatalf("asset %s was created by a failed batch", id)
+			}
+
+			return err
+		})
+	}
+
+	n.mustSubmit(orgRequirements, texts, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.NewAssetsBulk(ctx, []NewAssetInput{{ID: "REQ-1"}, {ID: "REQ-3"}})
+	})
+
+	_, body := n.lastEvent()
+
+	if assets := body.(*events.BulkEventPayload).Assets; len(assets) != 2 || assets[0].AssetID != "REQ-1" ||
+		assets[1].AssetID != "REQ-3" {
+		t.Fatalf("the event does not list the batch: %+v", assets)
+	}
+}
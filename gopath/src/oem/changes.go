@@ -0,0 +1,627 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Change request states, a pending change expires when another change of the requirement is accepted first
+const (
+	changePending  = "pending"
+	changeAccepted = "accepted"
+	changeRejected = "rejected"
+	changeExpired  = "expired"
+)
+
+// updateValueRationale is recorded on change requests opened through the deprecated UpdateValue
+const updateValueRationale = "Proposed through UpdateValue"
+
+// Objection is raised on a pending change by an organization owning an affected requirement. The change cannot be
+// accepted until the objecting organization resolves it.
+type Objection struct {
+	MSPID       string `json:"mspid"`
+	Identity    string `json:"identity"`
+	Reason      string `json:"reason"`
+	Time        string `json:"time"`
+	Resolved    bool   `json:"resolved"`
+	ResolvedBy  string `json:"resolvedby"`
+	ResolveTime string `json:"resolvetime"`
+}
+
+// ChangeRequest proposes a new text for a requirement. The text stays in the private collection named by Collection,
+// only its hash and the summary of the lines it changes are public.
+type ChangeRequest struct {
+	ID              string             `json:"id"`
+	ReqID           string             `json:"reqid"`
+	BaseRevision    int                `json:"baserevision"`
+	Hash            string             `json:"hash"`
+	Changes         events.DiffSummary `json:"changes"`
+	Collection      string             `json:"collection"`
+	Rationale       string             `json:"rationale"`
+	ProposerMSP     string             `json:"proposermsp"`
+	Proposer        string             `json:"proposer"`
+	ProposeTime     string             `json:"proposetime"`
+	Status          string             `json:"status"`
+	Objections      []*Objection       `json:"objections"`
+	DecisionMSP     string             `json:"decisionmsp"`
+	DecidedBy       string             `json:"decidedby"`
+	DecisionTime    string             `json:"decisiontime"`
+	Comment         string             `json:"comment"`
+	AppliedRevision int                `json:"appliedrevision"`
+	TxID            string             `json:"txid"`
+}
+
+// ProposeChange proposes the text read from the transient map as the next revision of a requirement. The owner and
+// the suppliers it was shared with can propose, every dependent is notified so its owner can object. The approvers
+// of the requirement receive the text as well so they can decide on it.
+func (cc *OEMContract) ProposeChange(ctx contractapi.TransactionContextInterface, reqID string,
+	rationale string) (*ChangeRequest, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mspID, err := authorizeReader(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rationale == "" {
+		return nil, errors.New("A rationale is required")
+	}
+
+	text, salt, err := transientText(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	proposer, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	proposeTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner must be able to read the proposed text when it accepts it
+	collection := privateCollection(mspID)
+
+	if mspID != req.Owner.MSPID {
+		collection = sharedCollection(req.Owner.MSPID, mspID)
+	}
+
+	// The summary is taken now, accepting the change is endorsed by suppliers who cannot read the current text. A
+	// migrated requirement starts from its public legacy text.
+	var baseText string
+
+	if req.Revision == 0 {
+		baseText, err = legacyText(ctx, reqID)
+	} else {
+		var current *ContentText
+		current, err = getContentText(ctx, collection, req.ContentID)
+
+		if current != nil {
+			baseText = current.Text
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	change := &ChangeRequest{ID: proposeTime + "-" + ctx.GetStub().GetTxID(), ReqID: reqID,
+		BaseRevision: req.Revision, Hash: contentHash(salt, text),
+		Changes: summarizeDiff(diffLines(baseText, text)), Collection: collection, Rationale: rationale,
+		ProposerMSP: mspID, Proposer: proposer, ProposeTime: proposeTime, Status: changePending,
+		Objections: []*Objection{}, TxID: ctx.GetStub().GetTxID()}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(changeObjectType, []string{reqID, change.ID})
+
+	proposed := &ContentText{ContentID: key, ReqID: reqID, Revision: req.Revision + 1, Text: text, Salt: salt}
+
+	err = putContentText(ctx, collection, proposed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, err := approverMSPs(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, approverMSP := range approvers {
+		// An approver that took the requirement over reads the proposal as its owner
+		if approverMSP == req.Owner.MSPID || sharedCollection(req.Owner.MSPID, approverMSP) == collection {
+			continue
+		}
+
+		err = putContentText(ctx, sharedCollection(req.Owner.MSPID, approverMSP), proposed)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = putChangeRequest(ctx, change)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return change, cc.raiseChangeEvent(ctx, events.ChangeProposed, change)
+}
+
+// ObjectToChange records the objection of an organization owning a requirement affected by a pending change
+func (cc *OEMContract) ObjectToChange(ctx contractapi.TransactionContextInterface, reqID string, changeID string,
+	reason string) (*ChangeRequest, error) {
+
+	change, err := cc.GetChangeRequest(ctx, reqID, changeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Status != changePending {
+		return nil, fmt.Errorf("Change %s of asset %s is already %s", changeID, reqID, change.Status)
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	impact, err := cc.GetImpactSet(ctx, reqID, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	affected := false
+
+	for _, entry := range impact {
+		affected = affected || entry.Owner.MSPID == mspID
+	}
+
+	if !affected {
+		return nil, fmt.Errorf("%s owns no requirement affected by asset %s", mspID, reqID)
+	}
+
+	for _, objection := range change.Objections {
+		if objection.MSPID == mspID {
+			return nil, fmt.Errorf("%s already objected to change %s of asset %s", mspID, changeID, reqID)
+		}
+	}
+
+	identity, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	objectionTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	change.Objections = append(change.Objections, &Objection{MSPID: mspID, Identity: identity, Reason: reason,
+		Time: objectionTime})
+
+	err = putChangeRequest(ctx, change)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return change, cc.raiseChangeEvent(ctx, events.ChangeObjected, change)
+}
+
+// ResolveObjection resolves the objection the caller's organization raised on a pending change, once the concern is
+// settled
+func (cc *OEMContract) ResolveObjection(ctx contractapi.TransactionContextInterface, reqID string, changeID string,
+	comment string) (*ChangeRequest, error) {
+
+	change, err := cc.GetChangeRequest(ctx, reqID, changeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Status != changePending {
+		return nil, fmt.Errorf("Change %s of asset %s is already %s", changeID, reqID, change.Status)
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	var objection *Objection
+
+	for _, candidate := range change.Objections {
+		if candidate.MSPID == mspID && !candidate.Resolved {
+			objection = candidate
+		}
+	}
+
+	if objection == nil {
+		return nil, fmt.Errorf("%s has no open objection to change %s of asset %s", mspID, changeID, reqID)
+	}
+
+	objection.ResolvedBy, err = ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	objection.ResolveTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objection.Resolved = true
+	change.Comment = comment
+
+	err = putChangeRequest(ctx, change)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return change, cc.raiseChangeEvent(ctx, events.ObjectionResolved, change)
+}
+
+// AcceptChange applies a pending change as the next revision of the requirement, the owner and the approver
+// organizations can accept once every objection is resolved. The proposed text is passed in the transient map as
+// returned by ReadChangeText. The other pending changes of the requirement expire, they were written against the
+// replaced text.
+func (cc *OEMContract) AcceptChange(ctx contractapi.TransactionContextInterface, reqID string, changeID string,
+	comment string) (*ChangeRequest, error) {
+
+	return cc.decideChange(ctx, reqID, changeID, changeAccepted, comment)
+}
+
+// RejectChange closes a pending change without applying it, the owner and the approver organizations can reject
+func (cc *OEMContract) RejectChange(ctx contractapi.TransactionContextInterface, reqID string, changeID string,
+	comment string) (*ChangeRequest, error) {
+
+	return cc.decideChange(ctx, reqID, changeID, changeRejected, comment)
+}
+
+// GetChangeRequest returns a change request of a requirement
+func (cc *OEMContract) GetChangeRequest(ctx contractapi.TransactionContextInterface, reqID string,
+	changeID string) (*ChangeRequest, error) {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(changeObjectType, []string{reqID, changeID})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Change %s of asset %s does not exist", changeID, reqID)
+	}
+
+	change := new(ChangeRequest)
+	err = json.Unmarshal(existing, change)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type ChangeRequest", key)
+	}
+
+	return change, nil
+}
+
+// ReadChangeText returns the proposed text of a change to the owner, the organization that proposed it and the
+// approver organizations
+func (cc *OEMContract) ReadChangeText(ctx contractapi.TransactionContextInterface, reqID string,
+	changeID string) (*ContentText, error) {
+
+	change, err := cc.GetChangeRequest(ctx, reqID, changeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(changeObjectType, []string{reqID, changeID})
+
+	if mspID == req.Owner.MSPID || mspID == change.ProposerMSP {
+		return getContentText(ctx, change.Collection, key)
+	}
+
+	approvers, err := approverMSPs(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !contains(approvers, mspID) {
+		return nil, &AccessError{ID: reqID, CallerMSP: mspID}
+	}
+
+	return getContentText(ctx, sharedCollection(req.Owner.MSPID, mspID), key)
+}
+
+// GetChangeRequests returns every change request of a requirement, oldest first
+func (cc *OEMContract) GetChangeRequests(ctx contractapi.TransactionContextInterface,
+	reqID string) ([]*ChangeRequest, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(changeObjectType, []string{reqID})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	changes := []*ChangeRequest{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read change requests from the world state")
+		}
+
+		change := new(ChangeRequest)
+		err = json.Unmarshal(kv.Value, change)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type ChangeRequest",
+				kv.Key)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// decideChange lets the owner or an approver organization accept or reject a pending change
+func (cc *OEMContract) decideChange(ctx contractapi.TransactionContextInterface, reqID string, changeID string,
+	decision string, comment string) (*ChangeRequest, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := cc.GetChangeRequest(ctx, reqID, changeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Status != changePending {
+		return nil, fmt.Errorf("Change %s of asset %s is already %s", changeID, reqID, change.Status)
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	approvers, err := approverMSPs(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mspID != req.Owner.MSPID && !contains(approvers, mspID) {
+		return nil, &AuthorizationError{ID: reqID, CallerMSP: mspID, OwnerMSP: req.Owner.MSPID}
+	}
+
+	change.DecidedBy, err = ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	change.DecisionTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	change.Status = decision
+	change.DecisionMSP = mspID
+	change.Comment = comment
+
+	if decision == changeRejected {
+		err = putChangeRequest(ctx, change)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return change, cc.raiseChangeEvent(ctx, events.ChangeRejected, change)
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, objection := range change.Objections {
+		if !objection.Resolved {
+			return nil, fmt.Errorf("Change %s of asset %s has an open objection of %s", changeID, reqID,
+				objection.MSPID)
+		}
+	}
+
+	// A change only applies to the text it was written against
+	if change.BaseRevision != req.Revision {
+		return nil, fmt.Errorf("Change %s was proposed against revision %d but asset %s is at revision %d",
+			changeID, change.BaseRevision, reqID, req.Revision)
+	}
+
+	// The suppliers endorsing the new revision cannot read the collection holding the proposal
+	text, salt, err := transientText(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contentHash(salt, text) != change.Hash {
+		return nil, fmt.Errorf("The text of change %s does not match its hash", changeID)
+	}
+
+	change.AppliedRevision = req.Revision + 1
+
+	err = putChangeRequest(ctx, change)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = cc.expireChanges(ctx, reqID, changeID, change.DecisionTime,
+		fmt.Sprintf("Revision %d was applied from change %s", change.AppliedRevision, changeID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The revision is written by the proposer, assetModified carries the change id and replaces any change event of
+	// this transaction
+	return change, cc.reviseContent(ctx, req, text, salt, change.Proposer, changeID, change.Changes)
+}
+
+// UpdateValue proposes the text read from the transient map as the next revision of a requirement.
+//
+// Deprecated: the text no longer changes at once, UpdateValue opens a change request like ProposeChange which the
+// owner or an approver has to accept. Use ProposeChange to give a rationale.
+func (cc *OEMContract) UpdateValue(ctx contractapi.TransactionContextInterface, id string) error {
+	_, err := cc.ProposeChange(ctx, id, updateValueRationale)
+
+	return err
+}
+
+// expireChanges closes the pending changes of a requirement other than exceptID, with comment as the reason
+func (cc *OEMContract) expireChanges(ctx contractapi.TransactionContextInterface, reqID string, exceptID string,
+	decisionTime string, comment string) error {
+
+	changes, err := cc.GetChangeRequests(ctx, reqID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.ID == exceptID || change.Status != changePending {
+			continue
+		}
+
+		change.Status = changeExpired
+		change.DecisionTime = decisionTime
+		change.Comment = comment
+
+		err = putChangeRequest(ctx, change)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// raiseChangeEvent notifies the owners of every affected requirement about a change request
+func (cc *OEMContract) raiseChangeEvent(ctx contractapi.TransactionContextInterface, eventType string,
+	change *ChangeRequest) error {
+
+	depIDs, err := cc.GetDependents(ctx, change.ReqID)
+
+	if err != nil {
+		return err
+	}
+
+	impact, err := cc.GetImpactSet(ctx, change.ReqID, 0)
+
+	if err != nil {
+		return err
+	}
+
+	return raiseEvent(ctx, eventType, change.ReqID, events.ChangePayload{ChangeID: change.ID,
+		BaseRevision: change.BaseRevision, Status: change.Status, Rationale: change.Rationale,
+		Changes: change.Changes, Comment: change.Comment, Dependents: depIDs, Impact: impactPayload(impact)})
+}
+
+// approverMSPs returns the approver organizations of the latest approval request of a requirement, if any
+func approverMSPs(ctx contractapi.TransactionContextInterface, reqID string) ([]string, error) {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{reqID})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return []string{}, nil
+	}
+
+	approval := new(ApprovalRequest)
+	err = json.Unmarshal(existing, approval)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type ApprovalRequest", key)
+	}
+
+	return approval.Approvers, nil
+}
+
+// putChangeRequest stores a change request under its requirement
+func putChangeRequest(ctx contractapi.TransactionContextInterface, change *ChangeRequest) error {
+	key, _ := ctx.GetStub().CreateCompositeKey(changeObjectType, []string{change.ReqID, change.ID})
+	changeBytes, _ := json.Marshal(change)
+
+	err := ctx.GetStub().PutState(key, changeBytes)
+
+	if err != nil {
+		return errors.New("Unable to commit the change request to the world state")
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// propose proposes text as the next revision of requirement id on behalf of mspID
+func (n *testNetwork) propose(mspID string, id string, text string) *ChangeRequest {
+	n.t.Helper()
+
+	var change *ChangeRequest
+
+	n.mustSubmit(mspID, withText(text), func(ctx contractapi.TransactionContextInterface) (err error) {
+		change, err = n.cc.ProposeChange(ctx, id, "Tighter braking target")
+		return err
+	})
+
+	return change
+}
+
+// changeText is the transient map carrying the proposed text of change as mspID reads it
+func (n *testNetwork) changeText(mspID string, id string, change *ChangeRequest) map[string]string {
+	n.t.Helper()
+
+	var text *ContentText
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		text, err = n.cc.ReadChangeText(ctx, id, change.ID)
+		return err
+	})
+
+	return map[string]string{transientTextKey: text.Text, transientSaltKey: text.Salt}
+}
+
+func TestOwnerAndApproversDecideChanges(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+
+	change := n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 38 m")
+
+	// The supplier proposing the change is no approver
+	err := n.mustFail(orgSupplier, n.changeText(orgSupplier, "REQ-1", change),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.AcceptChange(ctx, "REQ-1", change.ID, "")
+			return err
+		})
+
+	if _, ok := err.(*AuthorizationError); !ok {
+		t.Fatalf("expected an AuthorizationError, got %v", err)
+	}
+
+	// The approver reads the proposed text from the collection it shares with the owner and accepts it
+	n.accept(orgDesign, "REQ-1", change)
+
+	if req := n.requirement("REQ-1"); req.Revision != 2 {
+		t.Fatalf("expected revision 2, got %d", req.Revision)
+	}
+
+	// The owner accepts as well, approvers can reject, the supplier cannot
+	n.accept(orgRequirements, "REQ-1", n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 37 m"))
+
+	change = n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 30 m")
+
+	reject := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RejectChange(ctx, "REQ-1", change.ID, "Not feasible with the current brakes")
+		return err
+	}
+
+	n.mustFail(orgSupplier, nil, reject)
+	n.mustSubmit(orgDesign, nil, reject)
+}
+
+func TestAcceptedChangeNeedsApprovalAgain(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.approve(orgRequirements, "REQ-2", orgDesign)
+	n.share(orgRequirements, "REQ-2", orgSupplier)
+
+	// An approved requirement goes back to draft
+	n.accept(orgRequirements, "REQ-1", n.propose(orgRequirements, "REQ-1", "The vehicle shall stop within 38 m"))
+
+	if req := n.requirement("REQ-1"); req.Status != StatusDraft {
+		t.Fatalf("expected the revised requirement in %s, got %s", StatusDraft, req.Status)
+	}
+
+	// A shared one keeps its suppliers but is not shared further until the new revision is approved
+	n.accept(orgRequirements, "REQ-2", n.propose(orgSupplier, "REQ-2", "The brake pedal force shall not exceed 450 N"))
+
+	req := n.requirement("REQ-2")
+
+	if req.Status != StatusShared || req.ApprovedRevision != 1 || req.Revision != 2 {
+		t.Fatalf("expected a shared requirement awaiting approval of revision 2, got %+v", req)
+	}
+
+	n.mustFail(orgRequirements, n.contents(orgRequirements, "REQ-2"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.ShareAsset(ctx, "REQ-2", orgSimulation)
+			return err
+		})
+
+	n.approve(orgRequirements, "REQ-2", orgDesign)
+
+	if req := n.requirement("REQ-2"); req.Status != StatusShared || req.ApprovedRevision != 2 {
+		t.Fatalf("expected revision 2 approved while shared, got %+v", req)
+	}
+
+	n.share(orgRequirements, "REQ-2", orgSimulation)
+}
+
+func TestAcceptedChangeExpiresStaleChanges(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	first := n.propose(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 38 m")
+	second := n.propose(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 36 m")
+	stale := n.changeText(orgRequirements, "REQ-1", second)
+
+	n.accept(orgRequirements, "REQ-1", first)
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		second, err = n.cc.GetChangeRequest(ctx, "REQ-1", second.ID)
+		return err
+	})
+
+	if second.Status != changeExpired {
+		t.Fatalf("expected the change written against revision 1 to expire, got %s", second.Status)
+	}
+
+	n.mustFail(orgRequirements, stale, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcceptChange(ctx, "REQ-1", second.ID, "")
+		return err
+	})
+}
+
+func TestChangeEventSummarizesDiff(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop within 40 m\nThe brakes shall not fade")
+
+	change := n.propose(orgRequirements, "REQ-1", "The vehicle shall stop within 36 m\nThe brakes shall not fade")
+
+	_, body := n.lastEvent()
+	summary := body.(*events.ChangePayload).Changes
+
+	if summary.Changed != 1 || summary.Added != 0 || summary.Removed != 0 || len(summary.ChangedLines) != 1 ||
+		summary.ChangedLines[0] != 1 {
+		t.Fatalf("expected one changed line in the summary, got %+v", summary)
+	}
+
+	if change.Changes.Changed != 1 {
+		t.Fatalf("the change request does not keep its summary: %+v", change.Changes)
+	}
+}
+
+func TestUpdateValueOpensChangeRequest(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	n.mustSubmit(orgRequirements, withText("The vehicle shall stop from 100 km/h within 36 m"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.UpdateValue(ctx, "REQ-1")
+		})
+
+	if req := n.requirement("REQ-1"); req.Revision != 1 {
+		t.Fatalf("UpdateValue must not change the text at once, the requirement is at revision %d", req.Revision)
+	}
+
+	var changes []*ChangeRequest
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		changes, err = n.cc.GetChangeRequests(ctx, "REQ-1")
+		return err
+	})
+
+	if len(changes) != 1 || changes[0].Status != changePending || changes[0].Rationale != updateValueRationale {
+		t.Fatalf("expected one pending change request, got %+v", changes)
+	}
+
+	n.accept(orgRequirements, "REQ-1", changes[0])
+}
+
+func TestObjectionsBlockAcceptance(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "DES-1", "REQ-1")
+	})
+
+	change := n.propose(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 30 m")
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.ObjectToChange(ctx, "REQ-1", change.ID, "The disc cannot dissipate that much heat")
+		return err
+	})
+
+	accept := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcceptChange(ctx, "REQ-1", change.ID, "")
+		return err
+	}
+
+	proposed := n.changeText(orgRequirements, "REQ-1", change)
+	n.mustFail(orgRequirements, proposed, accept)
+
+	// Only the objecting organization resolves its objection
+	resolve := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.ResolveObjection(ctx, "REQ-1", change.ID, "Larger discs were approved")
+		return err
+	}
+
+	n.mustFail(orgRequirements, nil, resolve)
+	n.mustSubmit(orgDesign, nil, resolve)
+
+	envelope, _ := n.lastEvent()
+
+	if envelope.EventType != events.ObjectionResolved {
+		t.Fatalf("expected %s, got %s", events.ObjectionResolved, envelope.EventType)
+	}
+
+	n.mustFail(orgDesign, nil, resolve)
+	n.mustSubmit(orgRequirements, proposed, accept)
+}
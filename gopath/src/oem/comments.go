@@ -0,0 +1,407 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Comment is a clarification question or answer on a requirement. A comment without parent starts a thread, the
+// thread is resolved on its first comment. The text is kept in the collections the requirement owner shares with the
+// participants of the thread, only its salted hash is public.
+type Comment struct {
+	ID           string   `json:"id"`
+	ReqID        string   `json:"reqid"`
+	ParentID     string   `json:"parentid"`
+	ThreadID     string   `json:"threadid"`
+	Text         string   `json:"text,omitempty"`
+	Hash         string   `json:"hash"`
+	OwnerMSP     string   `json:"ownermsp"`
+	Participants []string `json:"participants"`
+	AuthorMSP    string   `json:"authormsp"`
+	Author       string   `json:"author"`
+	Time         string   `json:"time"`
+	TxID         string   `json:"txid"`
+	Resolved     bool     `json:"resolved"`
+	ResolvedBy   string   `json:"resolvedby"`
+	ResolveTime  string   `json:"resolvetime"`
+}
+
+// CommentText is the text of a comment as stored in the private data collections
+type CommentText struct {
+	CommentID string `json:"commentid"`
+	ReqID     string `json:"reqid"`
+	Text      string `json:"text"`
+	Salt      string `json:"salt"`
+}
+
+// CommentPage is one page of GetComments
+type CommentPage struct {
+	Comments     []*Comment `json:"comments"`
+	Bookmark     string     `json:"bookmark"`
+	FetchedCount int32      `json:"fetchedcount"`
+}
+
+// AddComment adds the comment passed in the transient map to a requirement, an empty parentCommentID starts a new
+// thread. A thread started by a supplier is between it and the owner, a thread started by the owner includes every
+// supplier the requirement is shared with.
+func (cc *OEMContract) AddComment(ctx contractapi.TransactionContextInterface, reqID string,
+	parentCommentID string) (*Comment, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the owner and the suppliers it was shared with take part in the discussion
+	mspID, err := authorizeReader(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text, salt, err := transientText(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if text == "" {
+		return nil, errors.New("A comment needs text")
+	}
+
+	author, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	commentTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Ids start with the transaction time so comments list in the order they were made
+	comment := &Comment{ID: commentTime + "-" + ctx.GetStub().GetTxID(), ReqID: reqID, ParentID: parentCommentID,
+		Hash: contentHash(salt, text), OwnerMSP: req.Owner.MSPID, Participants: []string{mspID}, AuthorMSP: mspID,
+		Author: author, Time: commentTime, TxID: ctx.GetStub().GetTxID()}
+	comment.ThreadID = comment.ID
+
+	if mspID == req.Owner.MSPID {
+		comment.Participants = append([]string{}, req.SharedWith...)
+	}
+
+	if parentCommentID != "" {
+		parent, err := getComment(ctx, reqID, parentCommentID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		thread, err := getComment(ctx, reqID, parent.ThreadID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if thread.Resolved {
+			return nil, fmt.Errorf("Thread %s of asset %s is resolved", thread.ID, reqID)
+		}
+
+		// Replies go to the organizations the thread started with
+		if mspID != thread.OwnerMSP && !contains(thread.Participants, mspID) {
+			return nil, &AccessError{ID: reqID, CallerMSP: mspID}
+		}
+
+		comment.ThreadID = thread.ID
+		comment.OwnerMSP = thread.OwnerMSP
+		comment.Participants = thread.Participants
+	}
+
+	err = putComment(ctx, comment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	textBytes, _ := json.Marshal(&CommentText{CommentID: comment.ID, ReqID: reqID, Text: text, Salt: salt})
+	key, _ := ctx.GetStub().CreateCompositeKey(commentObjectType, []string{reqID, comment.ID})
+
+	for _, collection := range comment.collections() {
+		if ctx.GetStub().PutPrivateData(collection, key, textBytes) != nil {
+			return nil, fmt.Errorf("Unable to write the comment text to collection %s", collection)
+		}
+	}
+
+	comment.Text = text
+
+	// Emit the event
+	err = raiseEvent(ctx, events.CommentAdded, reqID, events.CommentPayload{CommentID: comment.ID,
+		ParentID: comment.ParentID, ThreadID: comment.ThreadID, AuthorMSP: mspID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// ResolveThread closes a thread, only the requirement owner and the organization that opened the thread can
+func (cc *OEMContract) ResolveThread(ctx contractapi.TransactionContextInterface, reqID string,
+	threadID string) (*Comment, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mspID, err := authorizeReader(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := getComment(ctx, reqID, threadID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.ThreadID != thread.ID {
+		return nil, fmt.Errorf("Comment %s does not start a thread", threadID)
+	}
+
+	if mspID != req.Owner.MSPID && mspID != thread.AuthorMSP {
+		return nil, &AuthorizationError{ID: reqID, CallerMSP: mspID, OwnerMSP: req.Owner.MSPID}
+	}
+
+	if thread.Resolved {
+		return nil, fmt.Errorf("Thread %s of asset %s is already resolved", threadID, reqID)
+	}
+
+	thread.ResolvedBy, err = ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	thread.ResolveTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	thread.Resolved = true
+
+	err = putComment(ctx, thread)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.ThreadResolved, reqID, events.CommentPayload{CommentID: thread.ID,
+		ThreadID: thread.ID, AuthorMSP: mspID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+// GetComments returns a page of the comments on a requirement, oldest first, with the text of the comments the
+// caller's organization takes part in. Pass the returned bookmark for the next page.
+func (cc *OEMContract) GetComments(ctx contractapi.TransactionContextInterface, reqID string, pageSize int32,
+	bookmark string) (*CommentPage, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mspID, err := authorizeReader(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	iterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(commentObjectType,
+		[]string{reqID}, pageSize, bookmark)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	page := &CommentPage{Comments: []*Comment{}}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read comments from the world state")
+		}
+
+		comment := new(Comment)
+		err = json.Unmarshal(kv.Value, comment)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Comment", kv.Key)
+		}
+
+		collection := comment.collectionOf(mspID)
+
+		if collection != "" {
+			textBytes, err := ctx.GetStub().GetPrivateData(collection, kv.Key)
+
+			if err != nil {
+				return nil, fmt.Errorf("Unable to read from collection %s", collection)
+			}
+
+			text := new(CommentText)
+
+			if json.Unmarshal(textBytes, text) == nil {
+				comment.Text = text.Text
+			}
+		}
+
+		page.Comments = append(page.Comments, comment)
+	}
+
+	page.Bookmark = metadata.Bookmark
+	page.FetchedCount = metadata.FetchedRecordsCount
+
+	return page, nil
+}
+
+// resolveThreads resolves every open thread of a requirement started under ownerMSP, the texts of these threads stay
+// in the collections of ownerMSP
+func resolveThreads(ctx contractapi.TransactionContextInterface, reqID string, ownerMSP string, resolvedBy string,
+	resolveTime string) error {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(commentObjectType, []string{reqID})
+
+	if err != nil {
+		return errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	threads := []*Comment{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return errors.New("Unable to read comments from the world state")
+		}
+
+		comment := new(Comment)
+		err = json.Unmarshal(kv.Value, comment)
+
+		if err != nil {
+			return fmt.Errorf("Data retrieved from world state for key %s was not of type Comment", kv.Key)
+		}
+
+		if comment.ThreadID == comment.ID && comment.OwnerMSP == ownerMSP && !comment.Resolved {
+			threads = append(threads, comment)
+		}
+	}
+
+	for _, thread := range threads {
+		thread.Resolved = true
+		thread.ResolvedBy = resolvedBy
+		thread.ResolveTime = resolveTime
+
+		err = putComment(ctx, thread)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getComment reads a comment of a requirement from the world state
+func getComment(ctx contractapi.TransactionContextInterface, reqID string, id string) (*Comment, error) {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(commentObjectType, []string{reqID, id})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Comment %s on asset %s does not exist", id, reqID)
+	}
+
+	comment := new(Comment)
+	err = json.Unmarshal(existing, comment)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Comment", key)
+	}
+
+	return comment, nil
+}
+
+// collections lists the collections holding the text of the comment, the private collection of the owner while the
+// thread has no other participant
+func (comment *Comment) collections() []string {
+	collections := []string{}
+
+	for _, participant := range comment.Participants {
+		if participant != comment.OwnerMSP {
+			collections = append(collections, sharedCollection(comment.OwnerMSP, participant))
+		}
+	}
+
+	if len(collections) == 0 {
+		collections = append(collections, privateCollection(comment.OwnerMSP))
+	}
+
+	return collections
+}
+
+// collectionOf returns the collection mspID reads the text of the comment from, empty when it takes no part
+func (comment *Comment) collectionOf(mspID string) string {
+	if mspID == comment.OwnerMSP {
+		return comment.collections()[0]
+	}
+
+	if contains(comment.Participants, mspID) {
+		return sharedCollection(comment.OwnerMSP, mspID)
+	}
+
+	return ""
+}
+
+// putComment stores a comment under its requirement, without its text
+func putComment(ctx contractapi.TransactionContextInterface, comment *Comment) error {
+	key, _ := ctx.GetStub().CreateCompositeKey(commentObjectType, []string{comment.ReqID, comment.ID})
+
+	public := *comment
+	public.Text = ""
+	commentBytes, _ := json.Marshal(&public)
+
+	err := ctx.GetStub().PutState(key, commentBytes)
+
+	if err != nil {
+		return errors.New("Unable to commit the comment to the world state")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// comments returns the texts of the comments on requirement id as mspID sees them
+func (n *testNetwork) comments(mspID string, id string) []string {
+	n.t.Helper()
+
+	var page *CommentPage
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		page, err = n.cc.GetComments(ctx, id, 0, "")
+		return err
+	})
+
+	texts := []string{}
+
+	for _, comment := range page.Comments {
+		texts = append(texts, comment.Text)
+	}
+
+	return texts
+}
+
+func TestCommentTextStaysPrivate(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgSimulation)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.share(orgRequirements, "REQ-1", orgDesign)
+
+	question := "Is 40 m measured on a wet surface?"
+	var thread *Comment
+
+	n.mustSubmit(orgSupplier, withText(question), func(ctx contractapi.TransactionContextInterface) (err error) {
+		thread, err = n.cc.AddComment(ctx, "REQ-1", "")
+		return err
+	})
+
+	for key, value := range n.stub.State {
+		if strings.Contains(string(value), "wet surface") {
+			t.Fatalf("the comment text is public under %q", key)
+		}
+	}
+
+	// The other supplier learns a comment exists but cannot read it or join the thread
+	if texts := n.comments(orgDesign, "REQ-1"); len(texts) != 1 || texts[0] != "" {
+		t.Fatalf("the comment of another supplier is readable: %q", texts)
+	}
+
+	reply := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AddComment(ctx, "REQ-1", thread.ID)
+		return err
+	}
+
+	n.mustFail(orgDesign, withText("We measure on dry asphalt"), reply)
+
+	n.mustSubmit(orgRequirements, withText("Yes, on wet asphalt"), reply)
+
+	if texts := n.comments(orgSupplier, "REQ-1"); len(texts) != 2 || texts[0] != question ||
+		texts[1] != "Yes, on wet asphalt" {
+		t.Fatalf("the supplier cannot read its thread: %q", texts)
+	}
+
+	// A thread started by the owner reaches every supplier
+	n.mustSubmit(orgRequirements, withText("Please confirm the test track"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.AddComment(ctx, "REQ-1", "")
+			return err
+		})
+
+	for _, mspID := range []string{orgSupplier, orgDesign} {
+		if texts := n.comments(mspID, "REQ-1"); texts[len(texts)-1] != "Please confirm the test track" {
+			t.Fatalf("%s cannot read the thread of the owner: %q", mspID, texts)
+		}
+	}
+
+	// Text is required
+	n.mustFail(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AddComment(ctx, "REQ-1", "")
+		return err
+	})
+}
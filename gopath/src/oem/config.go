@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Names the paramnet and simulation chaincodes are deployed under unless the channel configures others
+const (
+	defaultParamChaincode = "paramcc"
+	defaultSimChaincode   = "simcc"
+)
+
+// ChaincodeConfig names the chaincodes on this channel the OEM contract reads from
+type ChaincodeConfig struct {
+	ParamChaincode string `json:"paramchaincode"`
+	SimChaincode   string `json:"simchaincode"`
+	UpdatedBy      string `json:"updatedby"`
+	UpdateTime     string `json:"updatetime"`
+	TxID           string `json:"txid"`
+}
+
+// SetChaincodeConfig stores the names the other chaincodes are deployed under, an empty name restores the default.
+// Parameter links and traceability trust whatever these chaincodes answer, so only admins of the governing
+// organization can change them.
+func (cc *OEMContract) SetChaincodeConfig(ctx contractapi.TransactionContextInterface,
+	paramChaincode string, simChaincode string) (*ChaincodeConfig, error) {
+
+	err := authorizeAdmin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if paramChaincode == "" {
+		paramChaincode = defaultParamChaincode
+	}
+
+	if simChaincode == "" {
+		simChaincode = defaultSimChaincode
+	}
+
+	updatedBy, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	updateTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ChaincodeConfig{ParamChaincode: paramChaincode, SimChaincode: simChaincode, UpdatedBy: updatedBy,
+		UpdateTime: updateTime, TxID: ctx.GetStub().GetTxID()}
+
+	key, err := configKey(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	configBytes, _ := json.Marshal(config)
+	err = ctx.GetStub().PutState(key, configBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the chaincode configuration to the world state")
+	}
+
+	return config, nil
+}
+
+// GetChaincodeConfig returns the chaincode names of this channel, the defaults when none were set
+func (cc *OEMContract) GetChaincodeConfig(ctx contractapi.TransactionContextInterface) (*ChaincodeConfig, error) {
+	return getChaincodeConfig(ctx)
+}
+
+// getChaincodeConfig reads the chaincode configuration of this channel
+func getChaincodeConfig(ctx contractapi.TransactionContextInterface) (*ChaincodeConfig, error) {
+
+	key, err := configKey(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	config := &ChaincodeConfig{ParamChaincode: defaultParamChaincode, SimChaincode: defaultSimChaincode}
+
+	if existing == nil {
+		return config, nil
+	}
+
+	err = json.Unmarshal(existing, config)
+
+	if err != nil {
+		return nil, errors.New("Data retrieved from world state was not of type ChaincodeConfig")
+	}
+
+	return config, nil
+}
+
+// configKey returns the world state key of the chaincode configuration of this channel
+func configKey(ctx contractapi.TransactionContextInterface) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(configObjectType, []string{ctx.GetStub().GetChannelID()})
+}
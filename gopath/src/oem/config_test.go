@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// fakeChaincode answers every call with the payload stored for its last argument, it stands in for the other
+// chaincodes of the channel
+type fakeChaincode struct {
+	payloads map[string]interface{}
+}
+
+func (f *fakeChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (f *fakeChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	payload, _ := json.Marshal(f.payloads[args[len(args)-1]])
+
+	return shim.Success(payload)
+}
+
+// deploy installs cc on the channel of the test network under name
+func (n *testNetwork) deploy(name string, cc shim.Chaincode) {
+	n.stub.MockPeerChaincode(name, shimtest.NewMockStub(name, cc), "")
+}
+
+func TestParamChaincodeIsConfigurable(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The brake disc diameter shall be 330 mm")
+	n.deploy("parameters", &fakeChaincode{payloads: map[string]interface{}{
+		"P-1": Parameter{ParamID: "P-1", Name: "brake disc diameter"}}})
+
+	link := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.LinkParameter(ctx, "REQ-1", "P-1")
+		return err
+	}
+
+	// Nothing is deployed under the default name
+	n.mustFail(orgRequirements, nil, link)
+
+	configure := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SetChaincodeConfig(ctx, "parameters", "")
+		return err
+	}
+
+	// Members and the admins of other organizations cannot point the contract at another chaincode
+	n.mustFail(orgRequirements, nil, configure)
+
+	for _, mspID := range []string{orgDesign, orgSupplier, orgSimulation} {
+		if n.submitAdmin(mspID, nil, configure) == nil {
+			t.Fatalf("an admin of %s changed the chaincode configuration", mspID)
+		}
+	}
+
+	err := n.submitAdmin(orgRequirements, nil, configure)
+
+	if err != nil {
+		t.Fatalf("configuring the chaincode names failed: %s", err)
+	}
+
+	n.mustSubmit(orgRequirements, nil, link)
+
+	var config *ChaincodeConfig
+
+	n.mustSubmit(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		config, err = n.cc.GetChaincodeConfig(ctx)
+		return err
+	})
+
+	if config.ParamChaincode != "parameters" || config.SimChaincode != defaultSimChaincode {
+		t.Fatalf("unexpected configuration %+v", config)
+	}
+}
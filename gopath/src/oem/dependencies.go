@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// DependencyEdge records that requirement From depends on requirement To
+type DependencyEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	CreateTime string `json:"createtime"`
+	TxID       string `json:"txid"`
+}
+
+// CycleError is returned when a new dependency would close a loop in the graph
+type CycleError struct {
+	ID        string
+	DependsOn string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Requirement %s cannot depend on %s, the dependency would create a cycle", e.ID, e.DependsOn)
+}
+
+// AddDependency records that requirement id depends on requirement dependsOnID, adding an existing edge fails
+func (cc *OEMContract) AddDependency(ctx contractapi.TransactionContextInterface, id string, dependsOnID string) error {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	// Only the owning organization can declare what its requirement depends on
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return err
+	}
+
+	return cc.addDependency(ctx, id, dependsOnID)
+}
+
+// RemoveDependency removes the edge between requirement id and requirement dependsOnID
+func (cc *OEMContract) RemoveDependency(ctx contractapi.TransactionContextInterface, id string, dependsOnID string) error {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return err
+	}
+
+	forwardKey, _ := ctx.GetStub().CreateCompositeKey(dependencyObjectType, []string{id, dependsOnID})
+	reverseKey, _ := ctx.GetStub().CreateCompositeKey(dependentObjectType, []string{dependsOnID, id})
+
+	existing, err := ctx.GetStub().GetState(forwardKey)
+
+	if err != nil {
+		return errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return fmt.Errorf("Requirement %s does not depend on %s", id, dependsOnID)
+	}
+
+	if ctx.GetStub().DelState(forwardKey) != nil || ctx.GetStub().DelState(reverseKey) != nil {
+		return errors.New("Unable to update the world state")
+	}
+
+	return nil
+}
+
+// GetDependents returns the IDs of the requirements that depend on requirement id
+func (cc *OEMContract) GetDependents(ctx contractapi.TransactionContextInterface, id string) ([]string, error) {
+	return listEdges(ctx, dependentObjectType, id)
+}
+
+// GetDependencies returns the IDs of the requirements that requirement id depends on
+func (cc *OEMContract) GetDependencies(ctx contractapi.TransactionContextInterface, id string) ([]string, error) {
+	return listEdges(ctx, dependencyObjectType, id)
+}
+
+// addDependency writes both directions of the edge after checking the endpoints and the graph
+func (cc *OEMContract) addDependency(ctx contractapi.TransactionContextInterface, id string, dependsOnID string) error {
+
+	if id == dependsOnID {
+		return &CycleError{ID: id, DependsOn: dependsOnID}
+	}
+
+	// Retired and withdrawn requirements take no new edges
+	for _, endpoint := range []string{id, dependsOnID} {
+		existing, err := getRequirementState(ctx, endpoint)
+
+		if err != nil {
+			return errors.New("Unable to interact with the world state")
+		}
+
+		if existing == nil {
+			return fmt.Errorf("Unable to find asset with id %s", endpoint)
+		}
+
+		end := new(Requirement)
+		err = json.Unmarshal(existing, end)
+
+		if err != nil {
+			return fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", endpoint)
+		}
+
+		err = end.checkOpen()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err := hasDependency(ctx, id, dependsOnID)
+
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("Requirement %s already depends on %s", id, dependsOnID)
+	}
+
+	// The new edge closes a loop if id is already reachable from dependsOnID
+	reachable, err := isReachable(ctx, dependsOnID, id)
+
+	if err != nil {
+		return err
+	}
+
+	if reachable {
+		return &CycleError{ID: id, DependsOn: dependsOnID}
+	}
+
+	return putDependencyEdge(ctx, id, dependsOnID)
+}
+
+// hasDependency reports whether the edge from requirement id to requirement dependsOnID exists
+func hasDependency(ctx contractapi.TransactionContextInterface, id string, dependsOnID string) (bool, error) {
+	forwardKey, _ := ctx.GetStub().CreateCompositeKey(dependencyObjectType, []string{id, dependsOnID})
+
+	existing, err := ctx.GetStub().GetState(forwardKey)
+
+	if err != nil {
+		return false, errors.New("Unable to interact with the world state")
+	}
+
+	return existing != nil, nil
+}
+
+// putDependencyEdge writes both directions of the edge without any checks
+func putDependencyEdge(ctx contractapi.TransactionContextInterface, id string, dependsOnID string) error {
+
+	forwardKey, _ := ctx.GetStub().CreateCompositeKey(dependencyObjectType, []string{id, dependsOnID})
+	reverseKey, _ := ctx.GetStub().CreateCompositeKey(dependentObjectType, []string{dependsOnID, id})
+
+	createTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	edge := DependencyEdge{From: id, To: dependsOnID, CreateTime: createTime, TxID: ctx.GetStub().GetTxID()}
+	edgeBytes, _ := json.Marshal(edge)
+
+	if ctx.GetStub().PutState(forwardKey, edgeBytes) != nil || ctx.GetStub().PutState(reverseKey, edgeBytes) != nil {
+		return errors.New("Unable to commit the dependency to the world state")
+	}
+
+	// The edge is endorsed like the requirement that depends
+	reqKey, _ := requirementKey(ctx, id)
+	policy, err := ctx.GetStub().GetStateValidationParameter(reqKey)
+
+	if err != nil {
+		return errors.New("Unable to read the endorsement policy from the world state")
+	}
+
+	if policy != nil {
+		if ctx.GetStub().SetStateValidationParameter(forwardKey, policy) != nil ||
+			ctx.GetStub().SetStateValidationParameter(reverseKey, policy) != nil {
+			return errors.New("Unable to set the endorsement policy in the world state")
+		}
+	}
+
+	return nil
+}
+
+// isReachable walks the dependencies of start looking for target
+func isReachable(ctx contractapi.TransactionContextInterface, start string, target string) (bool, error) {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		next, err := listEdges(ctx, dependencyObjectType, current)
+
+		if err != nil {
+			return false, err
+		}
+
+		for _, id := range next {
+			if id == target {
+				return true, nil
+			}
+
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// listEdges returns the far ends of all edges stored under objectType for requirement id
+func listEdges(ctx contractapi.TransactionContextInterface, objectType string, id string) ([]string, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{id})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	ids := []string{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read dependencies from the world state")
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+
+		if err != nil || len(attributes) != 2 {
+			return nil, fmt.Errorf("Malformed dependency key %s", kv.Key)
+		}
+
+		ids = append(ids, attributes[1])
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestCreateDependentRequiresOwner(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	// Only the owner of REQ-1 adds its dependents
+	err := n.mustFail(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.CreateDependent(ctx, "REQ-1", []string{"DES-1"})
+		return err
+	})
+
+	if _, ok := err.(*AuthorizationError); !ok {
+		t.Fatalf("expected an AuthorizationError, got %v", err)
+	}
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.CreateDependent(ctx, "REQ-1", []string{"DES-1"})
+		return err
+	})
+
+	var dependents []string
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		dependents, err = n.cc.GetDependents(ctx, "REQ-1")
+		return err
+	})
+
+	if len(dependents) != 1 || dependents[0] != "DES-1" {
+		t.Fatalf("expected DES-1 to depend on REQ-1, got %v", dependents)
+	}
+}
+
+func TestCreateDependentIsIdempotent(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+
+	for i := 0; i < 2; i++ {
+		n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.CreateDependent(ctx, "REQ-1", []string{"REQ-2"})
+			return err
+		})
+	}
+
+	// Adding the same edge explicitly is refused
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "REQ-2", "REQ-1")
+	})
+
+	var dependencies []string
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		dependencies, err = n.cc.GetDependencies(ctx, "REQ-2")
+		return err
+	})
+
+	if len(dependencies) != 1 || dependencies[0] != "REQ-1" {
+		t.Fatalf("expected REQ-2 to depend on REQ-1 once, got %v", dependencies)
+	}
+}
+
+func TestClosedRequirementTakesNoEdges(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	n.create(orgRequirements, "REQ-3", "The parking brake shall hold the vehicle on a 20 % slope")
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "REQ-2", "REQ-1")
+	})
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RetireRequirement(ctx, "REQ-1", "Covered by REQ-3")
+		return err
+	})
+
+	for _, edge := range [][2]string{{"REQ-1", "REQ-3"}, {"REQ-3", "REQ-1"}} {
+		err := n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.AddDependency(ctx, edge[0], edge[1])
+		})
+
+		if _, ok := err.(*ClosedError); !ok {
+			t.Fatalf("expected a ClosedError for %v, got %v", edge, err)
+		}
+	}
+
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.CreateDependent(ctx, "REQ-1", []string{"REQ-3"})
+		return err
+	})
+
+	err := n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.RemoveDependency(ctx, "REQ-1", "REQ-2")
+	})
+
+	if _, ok := err.(*ClosedError); !ok {
+		t.Fatalf("expected a ClosedError, got %v", err)
+	}
+}
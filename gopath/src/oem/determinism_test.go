@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// checkDeterministic endorses the proposal twice against the same ledger, as two peers would, and fails unless both
+// endorsements wrote the same values and raised the same event. The proposal stays committed afterwards.
+func checkDeterministic(n *testNetwork, mspID string, transient map[string]string, fn txFunc) {
+	n.t.Helper()
+
+	n.txs++
+	identity := &testIdentity{mspID: mspID}
+	txID := fmt.Sprintf("tx%03d", n.txs)
+	seconds := int64(1600000000 + n.txs)
+	snapshot := n.stub.snapshot()
+
+	err := n.run(identity, txID, seconds, transient, fn)
+
+	if err != nil {
+		n.t.Fatalf("first endorsement failed: %s", err)
+	}
+
+	first, firstEvent := n.stub.writes, n.stub.event
+	n.stub.restore(snapshot)
+
+	err = n.run(identity, txID, seconds, transient, fn)
+
+	if err != nil {
+		n.t.Fatalf("second endorsement failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(first, n.stub.writes) {
+		n.t.Fatalf("the endorsements wrote different values:\n%q\n%q", first, n.stub.writes)
+	}
+
+	if !reflect.DeepEqual(firstEvent, n.stub.event) {
+		n.t.Fatalf("the endorsements raised different events:\n%v\n%v", firstEvent, n.stub.event)
+	}
+}
+
+func TestTransactionsAreDeterministic(t *testing.T) {
+	n := newTestNetwork(t)
+	cc := n.cc
+
+	checkDeterministic(n, orgRequirements, withText("The vehicle shall stop from 100 km/h within 40 m"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			return cc.NewAsset(ctx, "REQ-1", Attributes{Priority: "high"})
+		})
+
+	texts, _ := json.Marshal(map[string]string{"REQ-2": "The brake pedal force shall not exceed 500 N",
+		"REQ-3": "The brake disc diameter shall be 330 mm"})
+
+	checkDeterministic(n, orgRequirements, map[string]string{transientTextsKey: string(texts),
+		transientSaltKey: testSalt}, func(ctx contractapi.TransactionContextInterface) error {
+		return cc.NewAssetsBulk(ctx, []NewAssetInput{{ID: "REQ-2"}, {ID: "REQ-3"}})
+	})
+
+	checkDeterministic(n, orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return cc.AddDependency(ctx, "REQ-2", "REQ-1")
+	})
+
+	checkDeterministic(n, orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.CreateDependent(ctx, "REQ-1", []string{"REQ-3"})
+		return err
+	})
+
+	checkDeterministic(n, orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.AddChild(ctx, "REQ-2", "REQ-3")
+		return err
+	})
+
+	var change *ChangeRequest
+
+	checkDeterministic(n, orgRequirements, withText("The vehicle shall stop from 100 km/h within 36 m"),
+		func(ctx contractapi.TransactionContextInterface) (err error) {
+			change, err = cc.ProposeChange(ctx, "REQ-1", "Tighter braking target")
+			return err
+		})
+
+	checkDeterministic(n, orgRequirements, n.changeText(orgRequirements, "REQ-1", change),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := cc.AcceptChange(ctx, "REQ-1", change.ID, "")
+			return err
+		})
+
+	checkDeterministic(n, orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.RequestApproval(ctx, "REQ-1", []string{orgDesign}, 0)
+		return err
+	})
+
+	checkDeterministic(n, orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.Approve(ctx, "REQ-1", "")
+		return err
+	})
+
+	checkDeterministic(n, orgRequirements, n.contents(orgRequirements, "REQ-1"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := cc.ShareAsset(ctx, "REQ-1", orgSupplier)
+			return err
+		})
+
+	checkDeterministic(n, orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.ReadAsset(ctx, "REQ-1")
+		return err
+	})
+
+	checkDeterministic(n, orgSupplier, withText("Is 36 m measured on a wet surface?"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := cc.AddComment(ctx, "REQ-1", "")
+			return err
+		})
+
+	checkDeterministic(n, orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.CreateBaseline(ctx, "BL-1", []string{"REQ-1", "REQ-2"}, RequirementFilter{})
+		return err
+	})
+
+	checkDeterministic(n, orgRequirements, n.contents(orgRequirements, "REQ-3"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := cc.OfferOwnership(ctx, "REQ-3", orgDesign)
+			return err
+		})
+
+	checkDeterministic(n, orgDesign, n.contents(orgDesign, "REQ-3"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			_, err := cc.AcceptOwnership(ctx, "REQ-3")
+			return err
+		})
+
+	checkDeterministic(n, orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := cc.RetireRequirement(ctx, "REQ-3", "Covered by REQ-1")
+		return err
+	})
+}
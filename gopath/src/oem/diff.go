@@ -0,0 +1,257 @@
+package main
+
+import (
+	"strings"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Diff operations
+const (
+	diffEqual  = "equal"
+	diffInsert = "insert"
+	diffDelete = "delete"
+	diffChange = "change"
+)
+
+// maxDiffCells bounds the work of one diff, comparing a and b fills a table of len(a)*len(b) cells. Past the bound
+// the differing part is reported as deleted and inserted as a whole.
+const maxDiffCells = 1 << 20
+
+// DiffWord is one word of a changed line
+type DiffWord struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffLine is one line of a content diff, FromLine and ToLine are 1-based and 0 where the line does not exist
+type DiffLine struct {
+	Op       string      `json:"op"`
+	FromLine int         `json:"fromline"`
+	ToLine   int         `json:"toline"`
+	FromText string      `json:"fromtext,omitempty"`
+	ToText   string      `json:"totext,omitempty"`
+	Words    []*DiffWord `json:"words,omitempty"`
+}
+
+// ContentDiff is the line by line difference between two revisions of a requirement text
+type ContentDiff struct {
+	ReqID        string             `json:"reqid"`
+	FromRevision int                `json:"fromrevision"`
+	ToRevision   int                `json:"torevision"`
+	Lines        []*DiffLine        `json:"lines"`
+	Summary      events.DiffSummary `json:"summary"`
+}
+
+// GetContentDiff compares two revisions of the requirement text, read from the collection the caller's organization
+// can read. Changed lines carry a word level diff.
+func (cc *OEMContract) GetContentDiff(ctx contractapi.TransactionContextInterface, id string, fromRev int,
+	toRev int) (*ContentDiff, error) {
+
+	from, err := cc.ReadContentText(ctx, id, fromRev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := cc.ReadContentText(ctx, id, toRev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := diffLines(from.Text, to.Text)
+
+	return &ContentDiff{ReqID: id, FromRevision: fromRev, ToRevision: toRev, Lines: lines,
+		Summary: summarizeDiff(lines)}, nil
+}
+
+// diffLines pairs deleted lines with the inserted lines that follow them into changed lines. The line and word diffs
+// share one budget of maxDiffCells, a revision runs the diff in every endorsement.
+func diffLines(fromText string, toText string) []*DiffLine {
+	budget := maxDiffCells
+	ops := diffTokens(strings.Split(fromText, "\n"), strings.Split(toText, "\n"), &budget)
+
+	lines := []*DiffLine{}
+	fromLine, toLine := 0, 0
+
+	for i := 0; i < len(ops); {
+		if ops[i].op == diffEqual {
+			fromLine++
+			toLine++
+			lines = append(lines, &DiffLine{Op: diffEqual, FromLine: fromLine, ToLine: toLine, ToText: ops[i].text})
+			i++
+			continue
+		}
+
+		deleted, inserted := []string{}, []string{}
+
+		for ; i < len(ops) && ops[i].op == diffDelete; i++ {
+			deleted = append(deleted, ops[i].text)
+		}
+
+		for ; i < len(ops) && ops[i].op == diffInsert; i++ {
+			inserted = append(inserted, ops[i].text)
+		}
+
+		for j := 0; j < len(deleted) || j < len(inserted); j++ {
+			line := &DiffLine{}
+
+			if j < len(deleted) {
+				fromLine++
+				line.Op, line.FromLine, line.FromText = diffDelete, fromLine, deleted[j]
+			}
+
+			if j < len(inserted) {
+				toLine++
+				line.Op, line.ToLine, line.ToText = diffInsert, toLine, inserted[j]
+			}
+
+			if j < len(deleted) && j < len(inserted) {
+				line.Op = diffChange
+				line.Words = diffWords(deleted[j], inserted[j], &budget)
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// diffWords compares two lines word by word
+func diffWords(from string, to string, budget *int) []*DiffWord {
+	words := []*DiffWord{}
+
+	for _, op := range diffTokens(strings.Fields(from), strings.Fields(to), budget) {
+		words = append(words, &DiffWord{Op: op.op, Text: op.text})
+	}
+
+	return words
+}
+
+// summarizeDiff counts the lines a diff adds, removes and changes and lists the lines touched in the newer revision
+func summarizeDiff(lines []*DiffLine) events.DiffSummary {
+	summary := events.DiffSummary{ChangedLines: []int{}}
+
+	for _, line := range lines {
+		switch line.Op {
+		case diffInsert:
+			summary.Added++
+		case diffDelete:
+			summary.Removed++
+		case diffChange:
+			summary.Changed++
+		default:
+			continue
+		}
+
+		if line.ToLine > 0 {
+			summary.ChangedLines = append(summary.ChangedLines, line.ToLine)
+		}
+	}
+
+	return summary
+}
+
+// diffOp is one token of an edit script
+type diffOp struct {
+	op   string
+	text string
+}
+
+// diffTokens returns the edit script turning a into b along their longest common subsequence, deletions before
+// insertions. Comparing the tokens between the common prefix and suffix takes cells from budget, when the budget is
+// too small they are deleted and inserted without comparing them.
+func diffTokens(a []string, b []string, budget *int) []diffOp {
+	prefix := 0
+
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := []diffOp{}
+
+	for _, token := range a[:prefix] {
+		ops = append(ops, diffOp{op: diffEqual, text: token})
+	}
+
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	cells := len(middleA) * len(middleB)
+
+	if cells > *budget {
+		for _, token := range middleA {
+			ops = append(ops, diffOp{op: diffDelete, text: token})
+		}
+
+		for _, token := range middleB {
+			ops = append(ops, diffOp{op: diffInsert, text: token})
+		}
+	} else {
+		*budget -= cells
+		ops = append(ops, diffLCS(middleA, middleB)...)
+	}
+
+	for _, token := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{op: diffEqual, text: token})
+	}
+
+	return ops
+}
+
+// diffLCS returns the edit script turning a into b along their longest common subsequence
+func diffLCS(a []string, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := []diffOp{}
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			ops = append(ops, diffOp{op: diffEqual, text: a[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			ops = append(ops, diffOp{op: diffDelete, text: a[i]})
+			i++
+		} else {
+			ops = append(ops, diffOp{op: diffInsert, text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{op: diffDelete, text: a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{op: diffInsert, text: b[j]})
+	}
+
+	return ops
+}
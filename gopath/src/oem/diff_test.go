@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffLinesPairsChangedLines(t *testing.T) {
+	lines := diffLines("The brake shall engage\nwithin 100 ms\nat any speed",
+		"The brake shall engage\nwithin 80 ms\nat any speed\nin any weather")
+
+	summary := summarizeDiff(lines)
+
+	if summary.Changed != 1 || summary.Added != 1 || summary.Removed != 0 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	if lines[1].Op != diffChange || len(lines[1].Words) != 4 || lines[1].Words[1].Op != diffDelete {
+		t.Fatalf("unexpected changed line %+v", lines[1])
+	}
+}
+
+func TestDiffLinesIsBounded(t *testing.T) {
+	from, to := []string{}, []string{}
+
+	for i := 0; i < 5000; i++ {
+		from = append(from, fmt.Sprintf("line %d of the old text", i))
+		to = append(to, fmt.Sprintf("line %d of the new text", i))
+	}
+
+	// Identical ends are matched without comparing, even past the budget
+	head := strings.Repeat("The brake shall engage within 100 ms\n", 5000)
+	lines := diffLines(head+strings.Join(from, "\n")+"\nend", head+strings.Join(to, "\n")+"\nend")
+
+	summary := summarizeDiff(lines)
+
+	if len(lines) != 10001 || summary.Changed != 5000 || summary.Added != 0 || summary.Removed != 0 {
+		t.Fatalf("unexpected summary of %d lines: %d changed, %d added, %d removed", len(lines), summary.Changed,
+			summary.Added, summary.Removed)
+	}
+
+	if lines[5000].FromLine != 5001 || lines[5000].ToLine != 5001 || lines[len(lines)-1].Op != diffEqual {
+		t.Fatalf("unexpected line numbers %+v", lines[5000])
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetEndorsementPolicy returns the organizations whose peers must endorse changes to a requirement
+func (cc *OEMContract) GetEndorsementPolicy(ctx contractapi.TransactionContextInterface, id string) ([]string, error) {
+
+	_, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := requirementKey(ctx, id)
+
+	policy, err := ctx.GetStub().GetStateValidationParameter(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to read the endorsement policy from the world state")
+	}
+
+	// No key-level policy means the chaincode-level policy applies
+	if policy == nil {
+		return []string{}, nil
+	}
+
+	ep, err := statebased.NewStateEP(policy)
+
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load the endorsement policy of asset %s", id)
+	}
+
+	return ep.ListOrgs(), nil
+}
+
+// SetEndorsementPolicy replaces the organizations whose peers must endorse changes to a requirement, its content
+// and its dependency edges. Only an admin of the owning organization can change it.
+func (cc *OEMContract) SetEndorsementPolicy(ctx contractapi.TransactionContextInterface, id string,
+	mspIDs []string) error {
+
+	if len(mspIDs) == 0 {
+		return errors.New("At least one organization is required")
+	}
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	err = authorizeOwnerAdmin(ctx, req)
+
+	if err != nil {
+		return err
+	}
+
+	return applyEndorsementPolicy(ctx, req, mspIDs)
+}
+
+// setEndorsementPolicy requires the owner and every supplier the requirement is shared with to endorse
+func setEndorsementPolicy(ctx contractapi.TransactionContextInterface, req *Requirement) error {
+	return applyEndorsementPolicy(ctx, req, append([]string{req.Owner.MSPID}, req.SharedWith...))
+}
+
+// applyEndorsementPolicy sets the key-level policy on the requirement, every content revision, the edges to the
+// requirements it depends on and its place below its parent
+func applyEndorsementPolicy(ctx contractapi.TransactionContextInterface, req *Requirement, mspIDs []string) error {
+
+	ep, err := statebased.NewStateEP(nil)
+
+	if err != nil {
+		return errors.New("Unable to create the endorsement policy")
+	}
+
+	err = ep.AddOrgs(statebased.RoleTypePeer, mspIDs...)
+
+	if err != nil {
+		return fmt.Errorf("Unable to add organizations to the endorsement policy: %s", err.Error())
+	}
+
+	policy, err := ep.Policy()
+
+	if err != nil {
+		return errors.New("Unable to create the endorsement policy")
+	}
+
+	key, _ := requirementKey(ctx, req.ID)
+	keys := []string{key}
+
+	for rev := 1; rev <= req.Revision; rev++ {
+		key, _ = contentKey(ctx, req.ID, rev)
+		keys = append(keys, key)
+	}
+
+	dependsOn, err := listEdges(ctx, dependencyObjectType, req.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, to := range dependsOn {
+		forwardKey, _ := ctx.GetStub().CreateCompositeKey(dependencyObjectType, []string{req.ID, to})
+		reverseKey, _ := ctx.GetStub().CreateCompositeKey(dependentObjectType, []string{to, req.ID})
+		keys = append(keys, forwardKey, reverseKey)
+	}
+
+	if req.ParentID != "" {
+		childKey, _ := ctx.GetStub().CreateCompositeKey(childObjectType, []string{req.ParentID, req.ID})
+		keys = append(keys, childKey)
+	}
+
+	for _, key := range keys {
+		err = ctx.GetStub().SetStateValidationParameter(key, policy)
+
+		if err != nil {
+			return errors.New("Unable to set the endorsement policy in the world state")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// policy returns the organizations that must endorse changes to requirement id in alphabetical order
+func (n *testNetwork) policy(id string) []string {
+	n.t.Helper()
+
+	var orgs []string
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		orgs, err = n.cc.GetEndorsementPolicy(ctx, id)
+		return err
+	})
+
+	sort.Strings(orgs)
+
+	return orgs
+}
+
+func TestSuppliersEndorseSharedRequirement(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	if orgs := n.policy("REQ-1"); !reflect.DeepEqual(orgs, []string{orgRequirements}) {
+		t.Fatalf("only the owner should endorse before sharing, the policy lists %v", orgs)
+	}
+
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.share(orgRequirements, "REQ-1", orgSimulation)
+
+	if orgs := n.policy("REQ-1"); !reflect.DeepEqual(orgs, []string{orgRequirements, orgSimulation, orgSupplier}) {
+		t.Fatalf("the owner and both suppliers should endorse, the policy lists %v", orgs)
+	}
+
+	// Neither supplier holds the owner's collection or the other supplier's, accepting the change still has to be
+	// endorsed by both
+	n.accept(orgRequirements, "REQ-1", n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 36 m"))
+
+	var text *ContentText
+
+	n.mustSubmit(orgSimulation, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		text, err = n.cc.ReadContentText(ctx, "REQ-1", 2)
+		return err
+	})
+
+	if text.Text != "The vehicle shall stop from 100 km/h within 36 m" {
+		t.Fatalf("the supplier did not receive the update: %+v", text)
+	}
+
+	// A text that does not match the public hash is refused
+	forged := n.changeText(orgRequirements, "REQ-1", n.propose(orgRequirements, "REQ-1", "Stop within 30 m"))
+	forged[transientTextKey] = "Stop within 50 m"
+
+	n.mustFail(orgRequirements, forged, func(ctx contractapi.TransactionContextInterface) error {
+		changes, err := n.cc.GetChangeRequests(ctx, "REQ-1")
+
+		if err != nil {
+			return err
+		}
+
+		_, err = n.cc.AcceptChange(ctx, "REQ-1", changes[len(changes)-1].ID, "")
+		return err
+	})
+
+	// Withdrawing the requirement drops the suppliers from the policy again
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.UnshareAsset(ctx, "REQ-1", "Replaced by REQ-2")
+		return err
+	})
+
+	if orgs := n.policy("REQ-1"); !reflect.DeepEqual(orgs, []string{orgRequirements}) {
+		t.Fatalf("only the owner should endorse after unsharing, the policy lists %v", orgs)
+	}
+}
+
+func TestOnlyOwnerAdminSetsEndorsementPolicy(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	set := func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.SetEndorsementPolicy(ctx, "DES-1", []string{orgDesign, orgSupplier})
+	}
+
+	// Neither a member of the owner nor an admin of another organization
+	n.mustFail(orgDesign, nil, set)
+
+	if err := n.submitAdmin(orgRequirements, nil, set); err == nil {
+		t.Fatal("an admin of another organization changed the endorsement policy")
+	}
+
+	if err := n.submitAdmin(orgDesign, nil, set); err != nil {
+		t.Fatalf("the owner's admin could not change the endorsement policy: %s", err)
+	}
+
+	if orgs := n.policy("DES-1"); !reflect.DeepEqual(orgs, []string{orgDesign, orgSupplier}) {
+		t.Fatalf("the policy was not replaced: %v", orgs)
+	}
+}
@@ -1,27 +1,37 @@
-package main
-
-//NewAssetPayload for events newAsset and assetAccessed
-type NewAssetPayload struct {
-	AssetID    string `json:"assetid"`
-	CreateTime string `json:"createime"`
-}
-
-//ReadAssetPayload for event readasset
-type ReadAssetPayload struct {
-	AssetID   string `json:"assetid"`
-	ShareTime string `json:"sharetime"`
-	ReadTime  string `json:"readtime"`
-}
-
-// ShareAssetPayload for event shareasset
-type ShareAssetPayload struct {
-	AssetID    string   `json:"assetid"`
-	ShareTime  string   `json:"sharetime"`
-	Dependents []string `json:"dependents"`
-}
-
-//DepEventPayload event payload
-type DepEventPayload struct {
-	SourceID   string   `json:"source"`
-	ListOfDeps []string `json:"dependents"`
-}
+package main
+
+import (
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// raiseEvent emits body as the event of the transaction about requirement assetID
+func raiseEvent(ctx contractapi.TransactionContextInterface, eventType string, assetID string,
+	body interface{}) error {
+
+	return events.Raise(ctx, eventType, events.AssetRequirement, assetID, body)
+}
+
+// impactPayload lists affected requirements with the organization owning each
+func impactPayload(impact []*ImpactEntry) []*events.ImpactEntry {
+	entries := []*events.ImpactEntry{}
+
+	for _, entry := range impact {
+		entries = append(entries, &events.ImpactEntry{ID: entry.ID, Depth: entry.Depth, OwnerMSP: entry.Owner.MSPID})
+	}
+
+	return entries
+}
+
+// signaturesPayload copies the signatures of an approval request
+func signaturesPayload(signatures []*Signature) []*events.Signature {
+	entries := []*events.Signature{}
+
+	for _, signature := range signatures {
+		entries = append(entries, &events.Signature{MSPID: signature.MSPID, Identity: signature.Identity,
+			Decision: signature.Decision, Comment: signature.Comment, Time: signature.Time, TxID: signature.TxID})
+	}
+
+	return entries
+}
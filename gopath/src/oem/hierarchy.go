@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// RequirementNode is a requirement with the requirements it decomposes into
+type RequirementNode struct {
+	Requirement *Requirement       `json:"requirement"`
+	Children    []*RequirementNode `json:"children"`
+}
+
+// HierarchyError is returned when a move would make a requirement its own ancestor
+type HierarchyError struct {
+	ID       string
+	ParentID string
+}
+
+func (e *HierarchyError) Error() string {
+	return fmt.Sprintf("Requirement %s cannot become a child of %s, it would be its own ancestor", e.ID, e.ParentID)
+}
+
+// ChildStatusError is returned when a parent is approved while requirements below it are still drafts
+type ChildStatusError struct {
+	ID     string
+	Drafts []string
+}
+
+func (e *ChildStatusError) Error() string {
+	return fmt.Sprintf("Requirement %s cannot be approved while %s are still drafts", e.ID,
+		strings.Join(e.Drafts, ", "))
+}
+
+// AddChild makes childID a child of parentID, use MoveRequirement for a child that already has a parent
+func (cc *OEMContract) AddChild(ctx contractapi.TransactionContextInterface, parentID string,
+	childID string) (*Requirement, error) {
+
+	child, err := cc.GetAsset(ctx, childID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if child.ParentID != "" {
+		return nil, fmt.Errorf("Asset %s is already a child of %s", childID, child.ParentID)
+	}
+
+	if parentID == "" {
+		return nil, errors.New("A parent is required")
+	}
+
+	return cc.setParent(ctx, child, parentID)
+}
+
+// AllowChild lets the owner of childID place it below parentID once, the caller must own the parent. Children block
+// the approval of their parent, so nobody else can attach them without this consent.
+func (cc *OEMContract) AllowChild(ctx contractapi.TransactionContextInterface, parentID string,
+	childID string) error {
+
+	parent, err := cc.GetAsset(ctx, parentID)
+
+	if err != nil {
+		return err
+	}
+
+	err = authorizeOwner(ctx, parent)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = cc.GetAsset(ctx, childID)
+
+	if err != nil {
+		return err
+	}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(consentObjectType, []string{parentID, childID})
+
+	if ctx.GetStub().PutState(key, []byte(childID)) != nil {
+		return errors.New("Unable to commit the consent to the world state")
+	}
+
+	return nil
+}
+
+// MoveRequirement moves a requirement under a new parent, an empty parentID makes it a root requirement
+func (cc *OEMContract) MoveRequirement(ctx contractapi.TransactionContextInterface, id string,
+	parentID string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ParentID == parentID {
+		return nil, fmt.Errorf("Asset %s is already below %s", id, parentID)
+	}
+
+	return cc.setParent(ctx, req, parentID)
+}
+
+// GetChildren returns the ids of the direct children of a requirement
+func (cc *OEMContract) GetChildren(ctx contractapi.TransactionContextInterface, id string) ([]string, error) {
+	return listEdges(ctx, childObjectType, id)
+}
+
+// GetRequirementTree returns the requirement with its children down to depth levels, a depth of 0 returns the whole
+// tree
+func (cc *OEMContract) GetRequirementTree(ctx contractapi.TransactionContextInterface, rootID string,
+	depth int) (*RequirementNode, error) {
+
+	root, err := cc.GetAsset(ctx, rootID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cc.buildTree(ctx, root, depth, 1)
+}
+
+// buildTree attaches the children of req while level is within depth
+func (cc *OEMContract) buildTree(ctx contractapi.TransactionContextInterface, req *Requirement, depth int,
+	level int) (*RequirementNode, error) {
+
+	node := &RequirementNode{Requirement: req, Children: []*RequirementNode{}}
+
+	if depth > 0 && level > depth {
+		return node, nil
+	}
+
+	childIDs, err := listEdges(ctx, childObjectType, req.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, childID := range childIDs {
+		child, err := cc.GetAsset(ctx, childID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		childNode, err := cc.buildTree(ctx, child, depth, level+1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = append(node.Children, childNode)
+	}
+
+	return node, nil
+}
+
+// setParent moves the child under parentID. The caller must own the child and be able to read the parent, so a
+// supplier can decompose a requirement shared with it into its own once the owner of the parent allowed it. Attaching
+// the child uses the consent up.
+func (cc *OEMContract) setParent(ctx contractapi.TransactionContextInterface, child *Requirement,
+	parentID string) (*Requirement, error) {
+
+	consentKey := ""
+
+	err := authorizeOwner(ctx, child)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = child.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != "" {
+		parent, err := cc.GetAsset(ctx, parentID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = authorizeReader(ctx, parent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.Owner.MSPID != child.Owner.MSPID {
+			key, _ := ctx.GetStub().CreateCompositeKey(consentObjectType, []string{parentID, child.ID})
+			consent, err := ctx.GetStub().GetState(key)
+
+			if err != nil {
+				return nil, errors.New("Unable to interact with the world state")
+			}
+
+			if consent == nil {
+				return nil, fmt.Errorf("%s did not allow asset %s below asset %s", parent.Owner.MSPID, child.ID,
+					parentID)
+			}
+
+			consentKey = key
+		}
+
+		err = parent.checkOpen()
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Walk up from the new parent, meeting the child means the move closes a loop
+		for ancestor := parent; ; {
+			if ancestor.ID == child.ID {
+				return nil, &HierarchyError{ID: child.ID, ParentID: parentID}
+			}
+
+			if ancestor.ParentID == "" {
+				break
+			}
+
+			ancestor, err = cc.GetAsset(ctx, ancestor.ParentID)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if child.ParentID != "" {
+		oldKey, _ := ctx.GetStub().CreateCompositeKey(childObjectType, []string{child.ParentID, child.ID})
+
+		if ctx.GetStub().DelState(oldKey) != nil {
+			return nil, errors.New("Unable to remove the child from the world state")
+		}
+	}
+
+	if consentKey != "" && ctx.GetStub().DelState(consentKey) != nil {
+		return nil, errors.New("Unable to remove the consent from the world state")
+	}
+
+	if parentID != "" {
+		err = putChildEdge(ctx, parentID, child.ID)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	child.ParentID = parentID
+
+	childBytes, _ := json.Marshal(child)
+	err = putRequirementState(ctx, child.ID, childBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	return child, nil
+}
+
+// putChildEdge records the child under its parent, endorsed like the child
+func putChildEdge(ctx contractapi.TransactionContextInterface, parentID string, childID string) error {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(childObjectType, []string{parentID, childID})
+
+	if ctx.GetStub().PutState(key, []byte(childID)) != nil {
+		return errors.New("Unable to commit the child to the world state")
+	}
+
+	reqKey, _ := requirementKey(ctx, childID)
+	policy, err := ctx.GetStub().GetStateValidationParameter(reqKey)
+
+	if err != nil {
+		return errors.New("Unable to read the endorsement policy from the world state")
+	}
+
+	if policy != nil && ctx.GetStub().SetStateValidationParameter(key, policy) != nil {
+		return errors.New("Unable to set the endorsement policy in the world state")
+	}
+
+	return nil
+}
+
+// checkChildStatus rolls the status of every requirement below id up to it, a parent cannot be approved while any
+// of them is a draft
+func (cc *OEMContract) checkChildStatus(ctx contractapi.TransactionContextInterface, id string) error {
+
+	drafts := []string{}
+	level := []string{id}
+
+	for len(level) > 0 {
+		next := []string{}
+
+		for _, current := range level {
+			childIDs, err := listEdges(ctx, childObjectType, current)
+
+			if err != nil {
+				return err
+			}
+
+			for _, childID := range childIDs {
+				child, err := cc.GetAsset(ctx, childID)
+
+				if err != nil {
+					return err
+				}
+
+				if child.Status == StatusDraft || child.Status == statusCreated {
+					drafts = append(drafts, childID)
+				}
+
+				next = append(next, childID)
+			}
+		}
+
+		level = next
+	}
+
+	if len(drafts) > 0 {
+		return &ChildStatusError{ID: id, Drafts: drafts}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestForeignChildNeedsParentOwnerConsent(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.create(orgSupplier, "SUP-1", "The caliper shall clamp with 30 kN")
+
+	addChild := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AddChild(ctx, "REQ-1", "SUP-1")
+		return err
+	}
+
+	n.mustFail(orgSupplier, nil, addChild)
+
+	// Only the owner of the parent can consent
+	allow := func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AllowChild(ctx, "REQ-1", "SUP-1")
+	}
+
+	n.mustFail(orgSupplier, nil, allow)
+	n.mustSubmit(orgRequirements, nil, allow)
+	n.mustSubmit(orgSupplier, nil, addChild)
+
+	var children []string
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		children, err = n.cc.GetChildren(ctx, "REQ-1")
+		return err
+	})
+
+	if len(children) != 1 || children[0] != "SUP-1" {
+		t.Fatalf("expected SUP-1 below REQ-1, got %v", children)
+	}
+
+	// Consent is given per child
+	n.create(orgSupplier, "SUP-2", "The pad shall withstand 700 C")
+
+	n.mustFail(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AddChild(ctx, "REQ-1", "SUP-2")
+		return err
+	})
+
+	// and used up by attaching it, once detached the child needs a new consent
+	n.mustSubmit(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.MoveRequirement(ctx, "SUP-1", "")
+		return err
+	})
+
+	n.mustFail(orgSupplier, nil, addChild)
+	n.mustSubmit(orgRequirements, nil, allow)
+	n.mustSubmit(orgSupplier, nil, addChild)
+}
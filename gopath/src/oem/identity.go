@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// governingMSP is the organization whose admins manage the channel wide state of the contract, such as the migration
+// of legacy keys
+const governingMSP = "RequirementsMSP"
+
+// ownerAttributes are the enrollment attributes copied onto the Owner when the certificate carries them
+var ownerAttributes = []string{"hf.EnrollmentID", "hf.Affiliation", "role"}
+
+// AuthorizationError is returned when the caller does not belong to the organization owning the asset
+type AuthorizationError struct {
+	ID        string
+	CallerMSP string
+	OwnerMSP  string
+}
+
+func (e *AuthorizationError) Error() string {
+	return fmt.Sprintf("Client from %s is not authorized to modify asset %s owned by %s", e.CallerMSP, e.ID,
+		e.OwnerMSP)
+}
+
+// AccessError is returned when the asset has not been shared with the caller's organization
+type AccessError struct {
+	ID        string
+	CallerMSP string
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("Asset %s has not been shared with %s", e.ID, e.CallerMSP)
+}
+
+// callerOwner builds the Owner from the certificate of the submitting client
+func callerOwner(ctx contractapi.TransactionContextInterface) (Owner, error) {
+	owner := Owner{}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return owner, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	cert, err := ctx.GetClientIdentity().GetX509Certificate()
+
+	if err != nil || cert == nil {
+		return owner, errors.New("Unable to read the certificate of the client identity")
+	}
+
+	owner.MSPID = mspID
+	owner.Subject = cert.Subject.String()
+
+	for _, name := range ownerAttributes {
+		value, found, err := ctx.GetClientIdentity().GetAttributeValue(name)
+
+		if err != nil {
+			return owner, fmt.Errorf("Unable to read attribute %s of the client identity", name)
+		}
+
+		if found {
+			if owner.Attributes == nil {
+				owner.Attributes = map[string]string{}
+			}
+			owner.Attributes[name] = value
+		}
+	}
+
+	return owner, nil
+}
+
+// authorizeOwner fails unless the caller belongs to the organization owning the requirement
+func authorizeOwner(ctx contractapi.TransactionContextInterface, req *Requirement) error {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	if mspID != req.Owner.MSPID {
+		return &AuthorizationError{ID: req.ID, CallerMSP: mspID, OwnerMSP: req.Owner.MSPID}
+	}
+
+	return nil
+}
+
+// authorizeReader fails unless the caller's organization owns the requirement or it was shared with it
+func authorizeReader(ctx contractapi.TransactionContextInterface, req *Requirement) (string, error) {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return "", errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	if mspID == req.Owner.MSPID || req.isSharedWith(mspID) {
+		return mspID, nil
+	}
+
+	return "", &AccessError{ID: req.ID, CallerMSP: mspID}
+}
+
+// authorizeAdmin fails unless the caller belongs to the governing organization and was enrolled with the attribute
+// role=admin. Every organization can enroll its own admins, so the attribute alone proves nothing.
+func authorizeAdmin(ctx contractapi.TransactionContextInterface) error {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	err = ctx.GetClientIdentity().AssertAttributeValue("role", "admin")
+
+	if mspID != governingMSP || err != nil {
+		return fmt.Errorf("Only clients of %s enrolled with role=admin can call this transaction", governingMSP)
+	}
+
+	return nil
+}
+
+// authorizeOwnerAdmin fails unless the caller belongs to the organization owning the requirement and was enrolled with
+// the attribute role=admin
+func authorizeOwnerAdmin(ctx contractapi.TransactionContextInterface, req *Requirement) error {
+	err := authorizeOwner(ctx, req)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetClientIdentity().AssertAttributeValue("role", "admin")
+
+	if err != nil {
+		return fmt.Errorf("Only clients of %s enrolled with role=admin can call this transaction", req.Owner.MSPID)
+	}
+
+	return nil
+}
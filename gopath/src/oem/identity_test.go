@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestOwnerComesFromCertificate(t *testing.T) {
+	n := newTestNetwork(t)
+
+	err := n.submitAdmin(orgDesign, withText("The brake disc diameter shall be 330 mm"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.NewAsset(ctx, "DES-1", Attributes{})
+		})
+
+	if err != nil {
+		t.Fatalf("creating the asset failed: %s", err)
+	}
+
+	owner := n.requirement("DES-1").Owner
+
+	if owner.MSPID != orgDesign || owner.Subject != "CN=user1@"+orgDesign {
+		t.Fatalf("the owner does not match the submitting certificate: %+v", owner)
+	}
+
+	if owner.Attributes["role"] != "admin" {
+		t.Fatalf("the enrollment attributes of the certificate were not kept: %v", owner.Attributes)
+	}
+
+	// Members carry no attributes
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	if owner := n.requirement("REQ-1").Owner; owner.MSPID != orgRequirements || owner.Attributes != nil {
+		t.Fatalf("unexpected owner %+v", owner)
+	}
+
+	// Another organization cannot act as the owner
+	share := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.ShareAsset(ctx, "REQ-1", orgSupplier)
+		return err
+	}
+
+	err = n.mustFail(orgDesign, n.contents(orgRequirements, "REQ-1"), share)
+
+	if _, ok := err.(*AuthorizationError); !ok {
+		t.Fatalf("expected an AuthorizationError, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ImpactEntry is a requirement affected by a change, Depth 1 being a direct dependent
+type ImpactEntry struct {
+	ID    string `json:"id"`
+	Depth int    `json:"depth"`
+	Owner Owner  `json:"owner"`
+}
+
+// GetImpactSet walks the dependents of requirement id up to maxDepth levels, a maxDepth of 0 walks the whole graph
+func (cc *OEMContract) GetImpactSet(ctx contractapi.TransactionContextInterface, id string,
+	maxDepth int) ([]*ImpactEntry, error) {
+
+	_, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	impact := []*ImpactEntry{}
+	visited := map[string]bool{id: true}
+	level := []string{id}
+
+	// Breadth first, so every requirement is reported at its shortest distance from id
+	for depth := 1; len(level) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		next := []string{}
+
+		for _, current := range level {
+			depIDs, err := cc.GetDependents(ctx, current)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, depID := range depIDs {
+				if visited[depID] {
+					continue
+				}
+				visited[depID] = true
+
+				dep, err := cc.GetAsset(ctx, depID)
+
+				if err != nil {
+					return nil, err
+				}
+
+				impact = append(impact, &ImpactEntry{ID: depID, Depth: depth, Owner: dep.Owner})
+				next = append(next, depID)
+			}
+		}
+
+		level = next
+	}
+
+	return impact, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestImpactSetDepths(t *testing.T) {
+	n := newTestNetwork(t)
+
+	// REQ-2 and REQ-3 depend on REQ-1, REQ-4 on both of them and REQ-5 on REQ-4
+	for _, id := range []string{"REQ-1", "REQ-2", "REQ-3", "REQ-4", "REQ-5"} {
+		n.create(orgRequirements, id, "Requirement "+id)
+	}
+
+	for _, edge := range [][2]string{{"REQ-2", "REQ-1"}, {"REQ-3", "REQ-1"}, {"REQ-4", "REQ-2"}, {"REQ-4", "REQ-3"},
+		{"REQ-5", "REQ-4"}} {
+		n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.AddDependency(ctx, edge[0], edge[1])
+		})
+	}
+
+	expected := map[string]int{"REQ-2": 1, "REQ-3": 1, "REQ-4": 2, "REQ-5": 3}
+
+	for maxDepth, size := range map[int]int{0: 4, 1: 2, 2: 3, 3: 4, 4: 4} {
+		var impact []*ImpactEntry
+
+		n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+			impact, err = n.cc.GetImpactSet(ctx, "REQ-1", maxDepth)
+			return err
+		})
+
+		if len(impact) != size {
+			t.Fatalf("expected %d entries up to depth %d, got %d", size, maxDepth, len(impact))
+		}
+
+		// Every requirement is reported once, at its shortest distance
+		for _, entry := range impact {
+			if entry.Depth != expected[entry.ID] || entry.Owner.MSPID != orgRequirements {
+				t.Fatalf("unexpected entry %+v up to depth %d", entry, maxDepth)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Composite key namespaces, every asset type is stored under its own object type so IDs of different types can
+// never collide and a lookup can only return the type it asks for
+const (
+	requirementObjectType = "requirement"
+	contentObjectType     = "content"
+	dependencyObjectType  = "dependency"
+	dependentObjectType   = "dependent"
+	alertObjectType       = "alert"
+	schemaObjectType      = "schema"
+	approvalObjectType    = "approval"
+	receiptObjectType     = "receipt"
+	ackObjectType         = "ack"
+	baselineObjectType    = "baseline"
+	childObjectType       = "child"
+	consentObjectType     = "childconsent"
+	commentObjectType     = "comment"
+	changeObjectType      = "change"
+	legacyObjectType      = "legacycontent"
+	configObjectType      = "config"
+
+	// parameterLinkObjectType indexes requirements by the paramnet parameters linked to them
+	parameterLinkObjectType = "parameterlink"
+)
+
+// requirementKey returns the world state key of requirement id
+func requirementKey(ctx contractapi.TransactionContextInterface, id string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(requirementObjectType, []string{id})
+}
+
+// contentKey returns the world state key of revision rev of requirement reqID
+func contentKey(ctx contractapi.TransactionContextInterface, reqID string, rev int) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(contentObjectType, []string{reqID, strconv.Itoa(rev)})
+}
+
+// getRequirementState reads the raw requirement id from the world state
+func getRequirementState(ctx contractapi.TransactionContextInterface, id string) ([]byte, error) {
+	key, err := requirementKey(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.GetStub().GetState(key)
+}
+
+// putRequirementState writes the raw requirement id to the world state
+func putRequirementState(ctx contractapi.TransactionContextInterface, id string, value []byte) error {
+	key, err := requirementKey(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, value)
+}
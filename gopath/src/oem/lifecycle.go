@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Requirement lifecycle states
+const (
+	StatusDraft      = "draft"
+	StatusInReview   = "in-review"
+	StatusApproved   = "approved"
+	StatusShared     = "shared"
+	StatusSuperseded = "superseded"
+	StatusWithdrawn  = "withdrawn"
+	StatusRetired    = "retired"
+
+	// statusCreated is the initial state written before the lifecycle existed, it behaves as draft
+	statusCreated = "created"
+)
+
+// transitions lists the states each state may legally move to
+var transitions = map[string][]string{
+	StatusDraft:      {StatusInReview, StatusRetired},
+	StatusInReview:   {StatusApproved, StatusDraft, StatusRetired},
+	StatusApproved:   {StatusShared, StatusDraft, StatusSuperseded, StatusRetired},
+	StatusShared:     {StatusSuperseded, StatusWithdrawn, StatusRetired},
+	StatusSuperseded: {StatusRetired},
+	StatusWithdrawn:  {StatusRetired},
+	StatusRetired:    {},
+}
+
+// UnknownStatusError is returned when a requirement holds a state outside the lifecycle
+type UnknownStatusError struct {
+	ID     string
+	Status string
+}
+
+func (e *UnknownStatusError) Error() string {
+	return fmt.Sprintf("Requirement %s has unknown status %s", e.ID, e.Status)
+}
+
+// TransitionError is returned when a requirement cannot move between two states
+type TransitionError struct {
+	ID   string
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("Requirement %s cannot move from %s to %s", e.ID, e.From, e.To)
+}
+
+// ClosedError is returned when a retired or withdrawn requirement is changed or shared
+type ClosedError struct {
+	ID     string
+	Status string
+}
+
+func (e *ClosedError) Error() string {
+	return fmt.Sprintf("Requirement %s is %s and can no longer be changed or shared", e.ID, e.Status)
+}
+
+// checkOpen fails once the requirement was retired or withdrawn
+func (req *Requirement) checkOpen() error {
+	if req.Status == StatusRetired || req.Status == StatusWithdrawn {
+		return &ClosedError{ID: req.ID, Status: req.Status}
+	}
+
+	return nil
+}
+
+// transitionTo moves the requirement to the given state if the transition table allows it
+func (req *Requirement) transitionTo(to string) error {
+	from := req.Status
+
+	if from == statusCreated {
+		from = StatusDraft
+	}
+
+	allowed, ok := transitions[from]
+
+	if !ok {
+		return &UnknownStatusError{ID: req.ID, Status: req.Status}
+	}
+
+	for _, next := range allowed {
+		if next == to {
+			req.Status = to
+			return nil
+		}
+	}
+
+	return &TransitionError{ID: req.ID, From: req.Status, To: to}
+}
+
+// SubmitForReview moves a draft requirement into review
+func (cc *OEMContract) SubmitForReview(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
+	return cc.changeStatus(ctx, id, StatusInReview)
+}
+
+// ReturnToDraft sends a requirement in review or approved back to draft
+func (cc *OEMContract) ReturnToDraft(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
+	return cc.changeStatus(ctx, id, StatusDraft)
+}
+
+// SupersedeRequirement marks an approved or shared requirement as superseded
+func (cc *OEMContract) SupersedeRequirement(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
+	return cc.changeStatus(ctx, id, StatusSuperseded)
+}
+
+// changeStatus applies a single transition, persists the requirement and raises statusChanged
+func (cc *OEMContract) changeStatus(ctx contractapi.TransactionContextInterface, id string,
+	to string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the owning organization moves its requirements through the lifecycle
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	from := req.Status
+
+	err = req.transitionTo(to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, id, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.StatusChanged, id, events.StatusChangePayload{From: from, To: to})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestChangeStatusRequiresOwner(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The brake shall engage within 100 ms")
+
+	err := n.mustFail(orgSupplier, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SubmitForReview(ctx, "REQ-1")
+		return err
+	})
+
+	if _, ok := err.(*AuthorizationError); !ok {
+		t.Fatalf("expected an AuthorizationError, got %v", err)
+	}
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SubmitForReview(ctx, "REQ-1")
+		return err
+	})
+
+	if status := n.requirement("REQ-1").Status; status != StatusInReview {
+		t.Fatalf("expected status %s, got %s", StatusInReview, status)
+	}
+}
+
+func TestChangeStatusFollowsTransitions(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The brake shall engage within 100 ms")
+
+	err := n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SupersedeRequirement(ctx, "REQ-1")
+		return err
+	})
+
+	if _, ok := err.(*TransitionError); !ok {
+		t.Fatalf("expected a TransitionError, got %v", err)
+	}
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SubmitForReview(ctx, "REQ-1")
+		return err
+	})
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.ReturnToDraft(ctx, "REQ-1")
+		return err
+	})
+
+	envelope, body := n.lastEvent()
+
+	if envelope.EventType != events.StatusChanged {
+		t.Fatalf("expected %s, got %s", events.StatusChanged, envelope.EventType)
+	}
+
+	change := body.(*events.StatusChangePayload)
+
+	if change.From != StatusInReview || change.To != StatusDraft {
+		t.Fatalf("unexpected transition %s -> %s", change.From, change.To)
+	}
+}
+
+func TestChangeStatusRefusesClosedRequirement(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The brake shall engage within 100 ms")
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RetireRequirement(ctx, "REQ-1", "obsolete")
+		return err
+	})
+
+	err := n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SubmitForReview(ctx, "REQ-1")
+		return err
+	})
+
+	if _, ok := err.(*ClosedError); !ok {
+		t.Fatalf("expected a ClosedError, got %v", err)
+	}
+}
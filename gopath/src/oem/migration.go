@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// legacyContent is the public Content record written before content revisions
+type legacyContent struct {
+	Text string `json:"text"`
+}
+
+// legacyOwner is the owner of a requirement written before owners were organizations, it only names a person
+type legacyOwner struct {
+	FirstName string `json:"firstname"`
+	LastName  string `json:"lastname"`
+}
+
+// MigrateKeys moves up to limit requirements stored under plain keys into the composite key namespaces, together
+// with the Content and Dependents records they point at. Every requirement keeps the owner stored with it. Call it
+// until it returns 0.
+func (cc *OEMContract) MigrateKeys(ctx contractapi.TransactionContextInterface, limit int) (int, error) {
+
+	err := authorizeAdmin(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// An open range only returns plain keys, composite keys are never visited
+	iterator, err := ctx.GetStub().GetStateByRange("", "")
+
+	if err != nil {
+		return 0, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	migrated := 0
+
+	for iterator.HasNext() && migrated < limit {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return 0, errors.New("Unable to read from the world state")
+		}
+
+		// Content and Dependents records are moved along with the requirement pointing at them
+		fields := map[string]json.RawMessage{}
+
+		if json.Unmarshal(kv.Value, &fields) != nil || fields["owner"] == nil {
+			continue
+		}
+
+		err = cc.migrateRequirement(ctx, kv.Key, kv.Value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// migrateRequirement rewrites one legacy requirement and deletes the plain keys it used. The legacy text was public,
+// it keeps its record under the legacy content namespace until the owner writes revision 1.
+func (cc *OEMContract) migrateRequirement(ctx contractapi.TransactionContextInterface, key string,
+	value []byte) error {
+
+	req := new(Requirement)
+	err := json.Unmarshal(value, req)
+
+	if err != nil {
+		return errors.New("Unable to load legacy requirement from JSON")
+	}
+
+	legacy := struct {
+		Owner legacyOwner `json:"owner"`
+	}{}
+	json.Unmarshal(value, &legacy)
+
+	// Owners written before they came from certificates only name a person, the name is kept but no organization
+	// owns such a requirement
+	req.DocType = requirementDocType
+
+	if legacy.Owner.FirstName != "" || legacy.Owner.LastName != "" {
+		req.Owner.Attributes = map[string]string{"firstname": legacy.Owner.FirstName,
+			"lastname": legacy.Owner.LastName}
+	}
+
+	if req.ID == "" {
+		req.ID = key
+	}
+
+	if req.Status == statusCreated {
+		req.Status = StatusDraft
+	}
+
+	// Content and Dependents created in the same second shared a key, in that case the text was already lost
+	if req.ContentID == req.DepID {
+		req.ContentID = ""
+	}
+
+	if req.Revision == 0 && req.ContentID != "" {
+		existing, err := ctx.GetStub().GetState(req.ContentID)
+
+		if err != nil {
+			return errors.New("Unable to interact with the world state")
+		}
+
+		legacyKey, _ := ctx.GetStub().CreateCompositeKey(legacyObjectType, []string{req.ID})
+
+		if ctx.GetStub().PutState(legacyKey, existing) != nil || ctx.GetStub().DelState(req.ContentID) != nil {
+			return errors.New("Unable to update the world state")
+		}
+
+		req.ContentID = legacyKey
+	}
+
+	// Every legacy dependent becomes an edge onto this requirement
+	if req.DepID != "" {
+		existing, err := ctx.GetStub().GetState(req.DepID)
+
+		if err != nil {
+			return errors.New("Unable to interact with the world state")
+		}
+
+		dependents := Dependents{}
+		json.Unmarshal(existing, &dependents)
+
+		for _, depID := range dependents.DepIDs {
+			err = putDependencyEdge(ctx, depID, req.ID)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if ctx.GetStub().DelState(req.DepID) != nil {
+			return errors.New("Unable to update the world state")
+		}
+
+		req.DepID = ""
+	}
+
+	reqBytes, _ := json.Marshal(req)
+
+	if putRequirementState(ctx, req.ID, reqBytes) != nil || ctx.GetStub().DelState(key) != nil {
+		return errors.New("Unable to update the world state")
+	}
+
+	if req.Owner.MSPID == "" {
+		return nil
+	}
+
+	return setEndorsementPolicy(ctx, req)
+}
+
+// legacyText returns the public text a migrated requirement had before its first revision
+func legacyText(ctx contractapi.TransactionContextInterface, reqID string) (string, error) {
+	legacyKey, _ := ctx.GetStub().CreateCompositeKey(legacyObjectType, []string{reqID})
+
+	existing, err := ctx.GetStub().GetState(legacyKey)
+
+	if err != nil {
+		return "", errors.New("Unable to interact with the world state")
+	}
+
+	text := legacyContent{}
+	json.Unmarshal(existing, &text)
+
+	return text.Text, nil
+}
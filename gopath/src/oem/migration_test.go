@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// legacyPerson is an owner written before owners came from certificates
+var legacyPerson = map[string]string{"firstname": "Ada", "lastname": "Lovelace"}
+
+// seedLegacy writes a requirement, its Content and its Dependents under plain keys as the old chaincode did
+func seedLegacy(n *testNetwork, id string, owner map[string]string, text string, dependents ...string) {
+	n.t.Helper()
+
+	legacy := map[string]interface{}{"id": id, "owner": owner, "contentid": "1500000000", "depid": "1500000001", "status": statusCreated, "createtime": "1500000000"}
+
+	legacyBytes, _ := json.Marshal(legacy)
+	contentBytes, _ := json.Marshal(legacyContent{Text: text})
+	dependentBytes, _ := json.Marshal(Dependents{ID: "1500000001", DepIDs: dependents})
+
+	n.stub.MockTransactionStart("legacy")
+	n.stub.MockStub.PutState(id, legacyBytes)
+	n.stub.MockStub.PutState("1500000000", contentBytes)
+	n.stub.MockStub.PutState("1500000001", dependentBytes)
+	n.stub.MockTransactionEnd("legacy")
+}
+
+// migrate runs MigrateKeys until nothing is left
+func migrate(n *testNetwork) {
+	n.t.Helper()
+
+	for migrated := 1; migrated > 0; {
+		err := n.submitAdmin(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+			migrated, err = n.cc.MigrateKeys(ctx, 10)
+			return err
+		})
+
+		if err != nil {
+			n.t.Fatalf("migration failed: %s", err)
+		}
+	}
+}
+
+func TestMigrationKeepsStoredOwner(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	seedLegacy(n, "REQ-1", legacyPerson, "The vehicle shall stop from 100 km/h within 40 m", "REQ-2")
+
+	// Only admins of the governing organization migrate
+	migrateKeys := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.MigrateKeys(ctx, 10)
+		return err
+	}
+
+	n.mustFail(orgRequirements, nil, migrateKeys)
+
+	if n.submitAdmin(orgDesign, nil, migrateKeys) == nil {
+		t.Fatal("an admin of another organization migrated the keys")
+	}
+
+	migrate(n)
+
+	req := n.requirement("REQ-1")
+
+	if req.Owner.MSPID != "" || req.Owner.Attributes["lastname"] != "Lovelace" {
+		t.Fatalf("the organization running the migration must not become the owner: %+v", req.Owner)
+	}
+
+	if req.Status != StatusDraft || req.DepID != "" || req.Revision != 0 {
+		t.Fatalf("the legacy requirement was not converted: %+v", req)
+	}
+
+	if n.stub.State["1500000001"] != nil || n.stub.State["1500000000"] != nil {
+		t.Fatal("the legacy Content and Dependents records must be moved")
+	}
+
+	var dependents []string
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		dependents, err = n.cc.GetDependents(ctx, "REQ-1")
+		return err
+	})
+
+	if len(dependents) != 1 || dependents[0] != "REQ-2" {
+		t.Fatalf("expected REQ-2 to depend on REQ-1, got %v", dependents)
+	}
+}
+
+func TestMigratedOwnerWritesFirstRevision(t *testing.T) {
+	n := newTestNetwork(t)
+	text := "The vehicle shall stop from 100 km/h within 40 m"
+	seedLegacy(n, "REQ-1", map[string]string{"mspid": orgDesign, "subject": "CN=user1@" + orgDesign}, text)
+	migrate(n)
+
+	req := n.requirement("REQ-1")
+
+	if req.Owner.MSPID != orgDesign || req.Owner.Attributes != nil {
+		t.Fatalf("the stored owner was not kept: %+v", req.Owner)
+	}
+
+	if orgs := n.policy("REQ-1"); len(orgs) != 1 || orgs[0] != orgDesign {
+		t.Fatalf("only the stored owner should endorse, the policy lists %v", orgs)
+	}
+
+	// The owner replaces the public legacy text with a private first revision
+	change := n.propose(orgDesign, "REQ-1", text)
+	n.accept(orgDesign, "REQ-1", change)
+
+	if req := n.requirement("REQ-1"); req.Revision != 1 {
+		t.Fatalf("expected revision 1, got %d", req.Revision)
+	}
+
+	legacyKey, _ := n.stub.CreateCompositeKey(legacyObjectType, []string{"REQ-1"})
+
+	if n.stub.State[legacyKey] != nil {
+		t.Fatal("the public legacy text survived the first revision")
+	}
+
+	var private *ContentText
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		private, err = n.cc.ReadContentText(ctx, "REQ-1", 1)
+		return err
+	})
+
+	if private.Text != text {
+		t.Fatalf("the text was not moved to the owner's collection: %+v", private)
+	}
+}
@@ -0,0 +1,735 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Organizations of the test network, see collections_config.json
+const (
+	orgRequirements = "RequirementsMSP"
+	orgDesign       = "DesignGroupMSP"
+	orgSupplier     = "SupplierMSP"
+	orgSimulation   = "SimulationGroupMSP"
+)
+
+// compositeKeyNamespace starts every composite key
+const compositeKeyNamespace = "\x00"
+
+// testSalt stands in for the random salt clients pass with every text
+const testSalt = "0123456789abcdef0123456789abcdef"
+
+// testStub is a MockStub that behaves closer to a peer: private data is restricted to collection members, failed
+// transactions are rolled back, key-level endorsement policies are checked on commit and the write set of the
+// current transaction is recorded
+type testStub struct {
+	*shimtest.MockStub
+
+	// members maps every collection to the organizations in its policy, writers to those allowed to write it
+	members map[string][]string
+	writers map[string][]string
+
+	caller      string
+	readFrom    map[string]bool
+	stateWrites map[string]bool
+	writes      map[string][]byte
+	policyFails []string
+	event       *peer.ChaincodeEvent
+}
+
+var memberPattern = regexp.MustCompile(`'([A-Za-z]+)\.member'`)
+
+// newTestStub loads the collection definitions and returns an empty ledger
+func newTestStub(t *testing.T) *testStub {
+	configBytes, err := ioutil.ReadFile("collections_config.json")
+
+	if err != nil {
+		t.Fatalf("reading collections_config.json: %s", err)
+	}
+
+	collections := []struct {
+		Name            string `json:"name"`
+		Policy          string `json:"policy"`
+		MemberOnlyWrite bool   `json:"memberOnlyWrite"`
+	}{}
+
+	err = json.Unmarshal(configBytes, &collections)
+
+	if err != nil {
+		t.Fatalf("parsing collections_config.json: %s", err)
+	}
+
+	members := map[string][]string{}
+	writers := map[string][]string{}
+	orgs := []string{}
+
+	for _, collection := range collections {
+		for _, match := range memberPattern.FindAllStringSubmatch(collection.Policy, -1) {
+			members[collection.Name] = append(members[collection.Name], match[1])
+
+			if !contains(orgs, match[1]) {
+				orgs = append(orgs, match[1])
+			}
+		}
+	}
+
+	for _, collection := range collections {
+		writers[collection.Name] = orgs
+
+		if collection.MemberOnlyWrite {
+			writers[collection.Name] = members[collection.Name]
+		}
+	}
+
+	return &testStub{MockStub: shimtest.NewMockStub("oemcc", nil), members: members, writers: writers}
+}
+
+// checkMember fails unless the client's organization belongs to the collection
+func (s *testStub) checkMember(collection string) error {
+	return s.checkIn(s.members, collection)
+}
+
+// checkWriter fails unless the client's organization may write the collection
+func (s *testStub) checkWriter(collection string) error {
+	return s.checkIn(s.writers, collection)
+}
+
+// checkIn fails unless the client's organization is listed for the collection
+func (s *testStub) checkIn(orgs map[string][]string, collection string) error {
+	members, ok := orgs[collection]
+
+	if !ok {
+		return fmt.Errorf("collection %s is not defined", collection)
+	}
+
+	if !contains(members, s.caller) {
+		return fmt.Errorf("%s cannot use collection %s", s.caller, collection)
+	}
+
+	return nil
+}
+
+// checkPolicy records a failure unless every organization required by the key-level policy of key can endorse
+func (s *testStub) checkPolicy(collection string, key string) {
+	policy, _ := s.MockStub.GetPrivateDataValidationParameter(collection, key)
+
+	if policy == nil {
+		return
+	}
+
+	ep, err := statebased.NewStateEP(policy)
+
+	if err != nil {
+		s.policyFails = append(s.policyFails, key)
+		return
+	}
+
+	for _, org := range ep.ListOrgs() {
+		if !s.canEndorse(org) {
+			s.policyFails = append(s.policyFails, fmt.Sprintf("%s needs %s", key, org))
+		}
+	}
+}
+
+// canEndorse reports whether the peers of org hold every private data collection the transaction reads
+func (s *testStub) canEndorse(org string) bool {
+	for collection := range s.readFrom {
+		if !contains(s.members[collection], org) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetPrivateData reads private data the client's organization is a member of
+func (s *testStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	err := s.checkMember(collection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.readFrom[collection] = true
+
+	return s.MockStub.GetPrivateData(collection, key)
+}
+
+// GetPrivateDataHash returns the hash of private data, every peer keeps it whether or not it holds the collection
+func (s *testStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	value, err := s.MockStub.GetPrivateData(collection, key)
+
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(value)
+
+	return hash[:], nil
+}
+
+// PutPrivateData writes private data the client's organization may write
+func (s *testStub) PutPrivateData(collection string, key string, value []byte) error {
+	err := s.checkWriter(collection)
+
+	if err != nil {
+		return err
+	}
+
+	s.writes[collection+"/"+key] = value
+
+	return s.MockStub.PutPrivateData(collection, key, value)
+}
+
+// DelPrivateData deletes private data the client's organization may write
+func (s *testStub) DelPrivateData(collection string, key string) error {
+	err := s.checkWriter(collection)
+
+	if err != nil {
+		return err
+	}
+
+	s.writes[collection+"/"+key] = nil
+	delete(s.PvtState[collection], key)
+
+	return nil
+}
+
+// PutState records the write and checks it against the key-level policy on commit
+func (s *testStub) PutState(key string, value []byte) error {
+	s.stateWrites[key] = true
+	s.writes[key] = value
+
+	return s.MockStub.PutState(key, value)
+}
+
+// DelState records the delete and checks it against the key-level policy on commit
+func (s *testStub) DelState(key string) error {
+	s.stateWrites[key] = true
+	s.writes[key] = nil
+
+	return s.MockStub.DelState(key)
+}
+
+// SetStateValidationParameter records the new policy, the policy in force before the transaction still applies to it
+func (s *testStub) SetStateValidationParameter(key string, ep []byte) error {
+	s.writes["policy/"+key] = ep
+
+	return s.MockStub.SetStateValidationParameter(key, ep)
+}
+
+// SetEvent keeps only the last event like the peer does
+func (s *testStub) SetEvent(name string, payload []byte) error {
+	s.event = &peer.ChaincodeEvent{EventName: name, Payload: payload}
+
+	return nil
+}
+
+// GetStateByPartialCompositeKeyWithPagination pages through GetStateByPartialCompositeKey, the bookmark is the last
+// key returned
+func (s *testStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+
+	partial, err := s.CreateCompositeKey(objectType, keys)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iterator := &sliceIterator{}
+
+	for _, key := range s.sortedKeys() {
+		if !strings.HasPrefix(key, partial) || key <= bookmark {
+			continue
+		}
+
+		if pageSize > 0 && len(iterator.results) == int(pageSize) {
+			break
+		}
+
+		iterator.results = append(iterator.results, &queryresult.KV{Key: key, Value: s.State[key]})
+	}
+
+	next := ""
+
+	if len(iterator.results) > 0 && len(iterator.results) == int(pageSize) {
+		next = iterator.results[len(iterator.results)-1].Key
+	}
+
+	return iterator, &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(iterator.results)),
+		Bookmark: next}, nil
+}
+
+// InvokeChaincode fails like a peer does when nothing is deployed under chaincodeName
+func (s *testStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) peer.Response {
+	if s.Invokables[chaincodeName] == nil {
+		return shim.Error(fmt.Sprintf("chaincode %s not found", chaincodeName))
+	}
+
+	return s.MockStub.InvokeChaincode(chaincodeName, args, channel)
+}
+
+// GetStateByRange returns the simple keys between startKey and endKey like a peer does, the open range "" to ""
+// never returns composite keys
+func (s *testStub) GetStateByRange(startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	iterator := &sliceIterator{}
+
+	for _, key := range s.sortedKeys() {
+		if strings.HasPrefix(key, compositeKeyNamespace) || key < startKey || (endKey != "" && key >= endKey) {
+			continue
+		}
+
+		iterator.results = append(iterator.results, &queryresult.KV{Key: key, Value: s.State[key]})
+	}
+
+	return iterator, nil
+}
+
+// GetQueryResult evaluates the selector of a CouchDB query against the world state
+func (s *testStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	iterator, _, err := s.GetQueryResultWithPagination(query, 0, "")
+
+	return iterator, err
+}
+
+// GetQueryResultWithPagination evaluates the selector of a CouchDB query and pages through the matching documents
+// in key order, the bookmark is the last key returned
+func (s *testStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+
+	parsed := struct {
+		Selector map[string]interface{} `json:"selector"`
+	}{}
+
+	err := json.Unmarshal([]byte(query), &parsed)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query %s: %s", query, err)
+	}
+
+	iterator := &sliceIterator{}
+
+	for _, key := range s.sortedKeys() {
+		if key <= bookmark {
+			continue
+		}
+
+		if pageSize > 0 && len(iterator.results) == int(pageSize) {
+			break
+		}
+
+		doc := map[string]interface{}{}
+
+		if json.Unmarshal(s.State[key], &doc) != nil || !matchSelector(parsed.Selector, doc) {
+			continue
+		}
+
+		iterator.results = append(iterator.results, &queryresult.KV{Key: key, Value: s.State[key]})
+	}
+
+	next := ""
+
+	if len(iterator.results) > 0 && len(iterator.results) == int(pageSize) {
+		next = iterator.results[len(iterator.results)-1].Key
+	}
+
+	return iterator, &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(iterator.results)),
+		Bookmark: next}, nil
+}
+
+// matchSelector supports the parts of the CouchDB selector syntax the chaincode uses: dotted field names, equality
+// and the operators $gte, $lte, $in and $nin
+func matchSelector(selector map[string]interface{}, doc map[string]interface{}) bool {
+	for field, condition := range selector {
+		var value interface{} = doc
+
+		for _, name := range strings.Split(field, ".") {
+			nested, ok := value.(map[string]interface{})
+
+			if !ok {
+				return false
+			}
+
+			value = nested[name]
+		}
+
+		operators, ok := condition.(map[string]interface{})
+
+		if !ok {
+			if fmt.Sprint(value) != fmt.Sprint(condition) {
+				return false
+			}
+
+			continue
+		}
+
+		for operator, operand := range operators {
+			matched := false
+
+			switch operator {
+			case "$gte":
+				matched = value != nil && fmt.Sprint(value) >= fmt.Sprint(operand)
+			case "$lte":
+				matched = value != nil && fmt.Sprint(value) <= fmt.Sprint(operand)
+			case "$in", "$nin":
+				found := false
+
+				for _, item := range operand.([]interface{}) {
+					found = found || fmt.Sprint(value) == fmt.Sprint(item)
+				}
+
+				matched = found == (operator == "$in")
+			}
+
+			if !matched {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sortedKeys lists the world state keys in order
+func (s *testStub) sortedKeys() []string {
+	keys := []string{}
+
+	for key := range s.State {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ledgerSnapshot is a copy of everything a transaction can change
+type ledgerSnapshot struct {
+	state    map[string][]byte
+	private  map[string]map[string][]byte
+	policies map[string]map[string][]byte
+}
+
+func copyNested(nested map[string]map[string][]byte) map[string]map[string][]byte {
+	copied := map[string]map[string][]byte{}
+
+	for name, values := range nested {
+		copied[name] = map[string][]byte{}
+
+		for key, value := range values {
+			copied[name][key] = value
+		}
+	}
+
+	return copied
+}
+
+func (s *testStub) snapshot() *ledgerSnapshot {
+	state := map[string][]byte{}
+
+	for key, value := range s.State {
+		state[key] = value
+	}
+
+	return &ledgerSnapshot{state: state, private: copyNested(s.PvtState),
+		policies: copyNested(s.EndorsementPolicies)}
+}
+
+func (s *testStub) restore(snapshot *ledgerSnapshot) {
+	s.PvtState = copyNested(snapshot.private)
+	s.EndorsementPolicies = copyNested(snapshot.policies)
+	s.State = map[string][]byte{}
+
+	for key, value := range snapshot.state {
+		s.State[key] = value
+	}
+
+	s.Keys = list.New()
+
+	for _, key := range s.sortedKeys() {
+		s.Keys.PushBack(key)
+	}
+}
+
+// sliceIterator iterates over query results collected up front
+type sliceIterator struct {
+	results []*queryresult.KV
+	next    int
+}
+
+func (it *sliceIterator) HasNext() bool {
+	return it.next < len(it.results)
+}
+
+func (it *sliceIterator) Next() (*queryresult.KV, error) {
+	if !it.HasNext() {
+		return nil, fmt.Errorf("iterator exhausted")
+	}
+
+	it.next++
+
+	return it.results[it.next-1], nil
+}
+
+func (it *sliceIterator) Close() error {
+	return nil
+}
+
+// testIdentity is the client identity of a member, or an admin, of one organization
+type testIdentity struct {
+	mspID string
+	admin bool
+}
+
+func (id *testIdentity) GetID() (string, error) {
+	return "x509::CN=user1@" + id.mspID + "::CN=ca." + id.mspID, nil
+}
+
+func (id *testIdentity) GetMSPID() (string, error) {
+	return id.mspID, nil
+}
+
+func (id *testIdentity) GetAttributeValue(name string) (string, bool, error) {
+	if name == "role" && id.admin {
+		return "admin", true, nil
+	}
+
+	return "", false, nil
+}
+
+func (id *testIdentity) AssertAttributeValue(name string, value string) error {
+	found, ok, _ := id.GetAttributeValue(name)
+
+	if !ok || found != value {
+		return fmt.Errorf("attribute %s is not %s", name, value)
+	}
+
+	return nil
+}
+
+func (id *testIdentity) GetX509Certificate() (*x509.Certificate, error) {
+	return &x509.Certificate{Subject: pkix.Name{CommonName: "user1@" + id.mspID}}, nil
+}
+
+// testNetwork runs transactions of the OEM contract one after another against a single ledger
+type testNetwork struct {
+	t    *testing.T
+	cc   *OEMContract
+	stub *testStub
+	txs  int
+}
+
+func newTestNetwork(t *testing.T) *testNetwork {
+	return &testNetwork{t: t, cc: new(OEMContract), stub: newTestStub(t)}
+}
+
+// txFunc is the body of one transaction
+type txFunc func(ctx contractapi.TransactionContextInterface) error
+
+// run executes fn as transaction txID of identity at the given time. A transaction that fails, or that writes keys
+// whose endorsement policy the organizations able to execute it cannot satisfy, leaves the ledger untouched.
+func (n *testNetwork) run(identity *testIdentity, txID string, seconds int64, transient map[string]string,
+	fn txFunc) error {
+
+	s := n.stub
+	snapshot := s.snapshot()
+
+	s.MockTransactionStart(txID)
+	defer s.MockTransactionEnd(txID)
+
+	s.TxTimestamp = &timestamppb.Timestamp{Seconds: seconds}
+	s.caller = identity.mspID
+	s.readFrom = map[string]bool{}
+	s.stateWrites = map[string]bool{}
+	s.writes = map[string][]byte{}
+	s.policyFails = nil
+	s.event = nil
+
+	transientMap := map[string][]byte{}
+
+	for key, value := range transient {
+		transientMap[key] = []byte(value)
+	}
+
+	s.SetTransient(transientMap)
+
+	ctx := new(contractapi.TransactionContext)
+	ctx.SetStub(s)
+	ctx.SetClientIdentity(identity)
+
+	// Policies are checked against the state before the transaction
+	policies := copyNested(s.EndorsementPolicies)
+	err := fn(ctx)
+
+	if err == nil {
+		current := s.EndorsementPolicies
+		s.EndorsementPolicies = policies
+
+		for key := range s.stateWrites {
+			s.checkPolicy("", key)
+		}
+
+		s.EndorsementPolicies = current
+
+		if len(s.policyFails) > 0 {
+			err = fmt.Errorf("endorsement policy failure: %s", strings.Join(s.policyFails, ", "))
+		}
+	}
+
+	if err != nil {
+		s.restore(snapshot)
+		s.event = nil
+	}
+
+	return err
+}
+
+// submit runs fn as the next transaction of a member of mspID
+func (n *testNetwork) submit(mspID string, transient map[string]string, fn txFunc) error {
+	n.txs++
+
+	return n.run(&testIdentity{mspID: mspID}, fmt.Sprintf("tx%03d", n.txs), int64(1600000000+n.txs),
+		transient, fn)
+}
+
+// submitAdmin runs fn as the next transaction of an admin of mspID
+func (n *testNetwork) submitAdmin(mspID string, transient map[string]string, fn txFunc) error {
+	n.txs++
+
+	return n.run(&testIdentity{mspID: mspID, admin: true}, fmt.Sprintf("tx%03d", n.txs),
+		int64(1600000000+n.txs), transient, fn)
+}
+
+// mustSubmit runs fn and fails the test when the transaction fails
+func (n *testNetwork) mustSubmit(mspID string, transient map[string]string, fn txFunc) {
+	n.t.Helper()
+
+	err := n.submit(mspID, transient, fn)
+
+	if err != nil {
+		n.t.Fatalf("transaction of %s failed: %s", mspID, err)
+	}
+}
+
+// mustFail runs fn and fails the test when the transaction succeeds
+func (n *testNetwork) mustFail(mspID string, transient map[string]string, fn txFunc) error {
+	n.t.Helper()
+
+	err := n.submit(mspID, transient, fn)
+
+	if err == nil {
+		n.t.Fatalf("transaction of %s succeeded, expected it to fail", mspID)
+	}
+
+	return err
+}
+
+// lastEvent decodes the event of the last successful transaction
+func (n *testNetwork) lastEvent() (*events.Envelope, interface{}) {
+	n.t.Helper()
+
+	if n.stub.event == nil {
+		n.t.Fatal("the last transaction raised no event")
+	}
+
+	envelope, body, err := events.Unmarshal(n.stub.event.Payload)
+
+	if err != nil {
+		n.t.Fatalf("decoding event %s: %s", n.stub.event.EventName, err)
+	}
+
+	return envelope, body
+}
+
+// withText is the transient map carrying a requirement text and its salt
+func withText(text string) map[string]string {
+	return map[string]string{transientTextKey: text, transientSaltKey: testSalt}
+}
+
+// contents is the transient map carrying the text of every revision of requirement id as mspID reads them
+func (n *testNetwork) contents(mspID string, id string) map[string]string {
+	n.t.Helper()
+
+	list := []*ContentText{}
+
+	for rev := 1; rev <= n.requirement(id).Revision; rev++ {
+		n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) error {
+			text, err := n.cc.ReadContentText(ctx, id, rev)
+			list = append(list, text)
+			return err
+		})
+	}
+
+	contentsBytes, _ := json.Marshal(list)
+
+	return map[string]string{transientContentsKey: string(contentsBytes)}
+}
+
+// requirement reads requirement id straight from the ledger
+func (n *testNetwork) requirement(id string) *Requirement {
+	n.t.Helper()
+
+	var req *Requirement
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		req, err = n.cc.GetAsset(ctx, id)
+		return err
+	})
+
+	return req
+}
+
+// create adds requirement id owned by mspID
+func (n *testNetwork) create(mspID string, id string, text string) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, withText(text), func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.NewAsset(ctx, id, Attributes{})
+	})
+}
+
+// approve takes requirement id of mspID through review with the given approvers
+func (n *testNetwork) approve(mspID string, id string, approvers ...string) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RequestApproval(ctx, id, approvers, 0)
+		return err
+	})
+
+	for _, approver := range approvers {
+		n.mustSubmit(approver, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.Approve(ctx, id, "")
+			return err
+		})
+	}
+}
+
+// share shares requirement id of mspID with supplier
+func (n *testNetwork) share(mspID string, id string, supplier string) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, n.contents(mspID, id), func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.ShareAsset(ctx, id, supplier)
+		return err
+	})
+}
@@ -1,347 +1,535 @@
-package main
-
-import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"strconv"
-	"time"
-
-	"github.com/hyperledger/fabric-contract-api-go/contractapi"
-)
-
-// OEMContract represents the contract
-type OEMContract struct {
-	contractapi.Contract
-}
-
-// NewAsset creates the new asset
-func (cc *OEMContract) NewAsset(ctx contractapi.TransactionContextInterface, id string, owner Owner, text string) error {
-	existing, err := ctx.GetStub().GetState(id)
-
-	if err != nil {
-		return errors.New("Unable to communicate wtih world state")
-	}
-
-	if existing != nil {
-		return fmt.Errorf("Asset with id %s, already exists", id)
-	}
-
-	// Create and persist new Content
-	contents := Content{ID: strconv.FormatInt(time.Now().Unix(), 10), Text: text}
-	contentBytes, _ := json.Marshal(contents)
-	ctx.GetStub().PutState(contents.ID, contentBytes)
-
-	// Create Dependents and persist
-	dependents := Dependents{ID: strconv.FormatInt(time.Now().Unix(), 10), DepIDs: []string{}}
-	dependentBytes, _ := json.Marshal(dependents)
-	ctx.GetStub().PutState(dependents.ID, dependentBytes)
-
-	ba := Requirement{}
-	ba.ID = id
-	ba.Owner = owner
-	ba.ContentID = contents.ID
-	ba.DepID = dependents.ID
-	ba.IsAccessed = false
-	ba.setInitialStatus()
-
-	// Get the time at creating the asset in World state
-	createTime := strconv.FormatInt(time.Now().Unix(), 10)
-	ba.CreateTime = createTime
-
-	// Convert to JSON
-	baBytes, _ := json.Marshal(ba)
-
-	// Commit to the ledger
-	err = ctx.GetStub().PutState(id, []byte(baBytes))
-
-	if err != nil {
-		return errors.New("Unable to commit the asset to the world state")
-	}
-
-	// Emit the event
-	newAssetPayload := NewAssetPayload{AssetID: ba.ID, CreateTime: createTime}
-	eventPayload, err := json.Marshal(newAssetPayload)
-
-	if err != nil {
-		return errors.New("Unable to marshal event payload to JSON")
-	}
-
-	err = ctx.GetStub().SetEvent("newAsset", []byte(eventPayload))
-
-	if err != nil {
-		return errors.New("Unable to raise event")
-	}
-
-	return nil
-}
-
-// ShareAssetsBulk creates requirements in BULK on peer
-func (cc *OEMContract) ShareAssetsBulk(ctx contractapi.TransactionContextInterface, input []string, owner Owner) error {
-
-	// Call the ShareAsset function for each element of the requirement array
-	for i := 0; i < len(input); i++ {
-
-		depIDs, err := cc.ShareAsset(ctx, input[i], owner)
-		if err != nil {
-			return err
-		}
-
-		// Build the payload
-		shareAssetPayload := ShareAssetPayload{AssetID: input[i], ShareTime: strconv.FormatInt(time.Now().Unix(), 10),
-			Dependents: depIDs}
-
-		eventPayload, err := json.Marshal(shareAssetPayload)
-		if err != nil {
-			return errors.New("Unable to marshal event payload to JSON")
-		}
-
-		// Emit the event
-		err = ctx.GetStub().SetEvent("assetShared", []byte(eventPayload))
-
-		if err != nil {
-			return errors.New("Unable to raise event")
-		}
-	}
-
-	return nil
-}
-
-// ShareAsset shares asset by changing status
-func (cc *OEMContract) ShareAsset(ctx contractapi.TransactionContextInterface, assetID string, owner Owner) ([]string, error) {
-
-	// Get the current asset
-	existing, err := ctx.GetStub().GetState(assetID)
-
-	if err != nil {
-		return nil, errors.New("Unable to interact with the world state")
-	}
-
-	if existing == nil {
-		return nil, fmt.Errorf("Unable to find asset with id %s", assetID)
-	}
-
-	// convert to the BasicAsset
-	req := Requirement{}
-	json.Unmarshal(existing, &req)
-
-	// set new owner
-	req.Owner = owner
-
-	// Update the status
-	req.setStatusShared()
-
-	// Get the time at sharing the asset in World state
-	req.ShareTime = strconv.FormatInt(time.Now().Unix(), 10)
-
-	// Commit back to ledger
-	baBytes, _ := json.Marshal(req)
-	err = ctx.GetStub().PutState(assetID, []byte(baBytes))
-
-	if err != nil {
-		return nil, errors.New("Unable to update the world state")
-	}
-
-	// Return dependents for shared assets
-	existingDep, _ := ctx.GetStub().GetState(req.DepID)
-
-	dependents := Dependents{}
-	json.Unmarshal(existingDep, &dependents)
-
-	return dependents.DepIDs, nil
-}
-
-// CreateDependent using from and to id
-func (cc *OEMContract) CreateDependent(ctx contractapi.TransactionContextInterface, fromID string,
-	toIDs []string) (*Requirement, error) {
-
-	// Get the start end
-	fromEnd, err := ctx.GetStub().GetState(fromID)
-
-	if err != nil {
-		return nil, errors.New("Unable to communicate with the World state")
-	}
-
-	if fromEnd == nil {
-		return nil, fmt.Errorf("Unable to find the asset with %s", fromID)
-	}
-
-	// load the start end
-	start := new(Requirement)
-	err = json.Unmarshal(fromEnd, start)
-
-	if err != nil {
-		return nil, errors.New("Unable to load start end from JSON")
-	}
-
-	// Pesrsist Dependent to World state
-	dependents := new(Dependents)
-	dependents.ID = strconv.FormatInt(time.Now().Unix(), 10)
-	dependents.DepIDs = toIDs
-
-	dependentBytes, _ := json.Marshal(dependents)
-	ctx.GetStub().PutState(dependents.ID, dependentBytes)
-
-	// Add the end as dependency on the start
-	start.DepID = dependents.ID
-
-	// convert to JSON
-	startBytes, _ := json.Marshal(start)
-
-	// save the start back into world state
-	err = ctx.GetStub().PutState(fromID, startBytes)
-
-	return start, nil
-}
-
-// UpdateValue updates the asset value
-func (cc *OEMContract) UpdateValue(ctx contractapi.TransactionContextInterface, id string, newText string) error {
-
-	// Get the current asset
-	existing, err := ctx.GetStub().GetState(id)
-
-	if err != nil {
-		return errors.New("Unable to interact with the world state")
-	}
-
-	if existing == nil {
-		return fmt.Errorf("Unable to find asset with id %s", id)
-	}
-
-	// convert to the BasicAsset
-	ba := new(Requirement)
-	json.Unmarshal(existing, ba)
-
-	// Get the contents for this requirement
-	existingContent, _ := ctx.GetStub().GetState(ba.DepID)
-	content := new(Content)
-	json.Unmarshal(existingContent, content)
-
-	// set the new text
-	content.Text = newText
-
-	// Commit back to ledger
-	contentBytes, _ := json.Marshal(content)
-	err = ctx.GetStub().PutState(content.ID, contentBytes)
-
-	if err != nil {
-		return errors.New("Unable to update the world state")
-	}
-
-	// Get the dependents
-	dependents := new(Dependents)
-	existingDep, _ := ctx.GetStub().GetState(ba.DepID)
-	json.Unmarshal(existingDep, dependents)
-
-	// Build the payload
-	depPayload := DepEventPayload{SourceID: ba.ID, ListOfDeps: dependents.DepIDs}
-
-	eventPayload, err := json.Marshal(depPayload)
-	if err != nil {
-		return errors.New("Unable to marshal event payload to JSON")
-	}
-
-	// Emit the event
-	err = ctx.GetStub().SetEvent("assetModified", []byte(eventPayload))
-
-	if err != nil {
-		return errors.New("Unable to raise event")
-	}
-
-	return nil
-}
-
-// ReadAsset returns the basic asset with id given from the world state
-func (cc *OEMContract) ReadAsset(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
-	existing, err := ctx.GetStub().GetState(id)
-
-	if err != nil {
-		return nil, errors.New("Unable to interact with the world state")
-	}
-
-	if existing == nil {
-		return nil, fmt.Errorf("Cannot read world state pair with key %s. Does not exist", id)
-	}
-
-	ba := new(Requirement)
-
-	err = json.Unmarshal(existing, ba)
-
-	if err != nil {
-		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", id)
-	}
-
-	// Raise the event if this asset is accessed first time after sharing
-	if !ba.IsAccessed {
-		ba.IsAccessed = true
-
-		// Update world state / ledger
-		baBytes, _ := json.Marshal(ba)
-		err = ctx.GetStub().PutState(id, []byte(baBytes))
-
-		if err != nil {
-			return nil, errors.New("Unable to update World state")
-		}
-
-		// Build the payload
-		assetAccessPayload := ReadAssetPayload{AssetID: ba.ID, ShareTime: ba.ShareTime,
-			ReadTime: strconv.FormatInt(time.Now().Unix(), 10)}
-
-		eventPayload, err := json.Marshal(assetAccessPayload)
-
-		if err != nil {
-			return nil, errors.New("Unable to marshal event payload to JSON")
-		}
-
-		// Emit the event
-		err = ctx.GetStub().SetEvent("assetAccessed", []byte(eventPayload))
-
-		if err != nil {
-			return nil, errors.New("Unable to raise event")
-		}
-	}
-
-	return ba, nil
-}
-
-// GetAsset returns the basic asset with id given from the world state
-func (cc *OEMContract) GetAsset(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
-	existing, err := ctx.GetStub().GetState(id)
-
-	if err != nil {
-		return nil, errors.New("Unable to interact with the world state")
-	}
-
-	if existing == nil {
-		return nil, fmt.Errorf("Cannot read world state pair with key %s. Does not exist", id)
-	}
-
-	ba := new(Requirement)
-
-	err = json.Unmarshal(existing, ba)
-
-	if err != nil {
-		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", id)
-	}
-
-	return ba, nil
-}
-
-// GetEvaluateTransactions returns functions of ComplexContract not to be tagged as submit
-func (cc *OEMContract) GetEvaluateTransactions() []string {
-	return []string{"GetAsset"}
-}
-
-func main() {
-	oemContract := new(OEMContract)
-
-	cc, err := contractapi.NewChaincode(oemContract)
-
-	if err != nil {
-		panic(err.Error())
-	}
-
-	if err := cc.Start(); err != nil {
-		panic(err.Error())
-	}
-}
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// OEMContract represents the contract
+type OEMContract struct {
+	contractapi.Contract
+}
+
+// NewAsset creates the new asset owned by the calling client, the text is read from the transient map
+func (cc *OEMContract) NewAsset(ctx contractapi.TransactionContextInterface, id string, attributes Attributes) error {
+	text, salt, err := transientText(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	err = cc.checkNewAsset(ctx, id, attributes)
+
+	if err != nil {
+		return err
+	}
+
+	ba, err := cc.createAsset(ctx, id, attributes, text, salt)
+
+	if err != nil {
+		return err
+	}
+
+	// Emit the event
+	return raiseEvent(ctx, events.NewAsset, ba.ID,
+		events.NewAssetPayload{CreateTime: ba.CreateTime, Revision: ba.Revision, Status: ba.Status})
+}
+
+// checkNewAsset verifies a requirement can be created without writing anything
+func (cc *OEMContract) checkNewAsset(ctx contractapi.TransactionContextInterface, id string,
+	attributes Attributes) error {
+
+	existing, err := getRequirementState(ctx, id)
+
+	if err != nil {
+		return errors.New("Unable to communicate wtih world state")
+	}
+
+	if existing != nil {
+		return fmt.Errorf("Asset with id %s, already exists", id)
+	}
+
+	// Reject attributes that do not match the channel schema
+	err = cc.validateAttributes(ctx, id, attributes)
+
+	if err != nil {
+		return err
+	}
+
+	// Linked parameters must exist in paramnet
+	for _, paramID := range attributes.ParameterIDs {
+		_, err = fetchParameter(ctx, paramID)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createAsset writes a checked requirement owned by the calling client together with its first revision
+func (cc *OEMContract) createAsset(ctx contractapi.TransactionContextInterface, id string, attributes Attributes,
+	text string, salt string) (*Requirement, error) {
+
+	// The owner always comes from the submitting certificate
+	owner, err := callerOwner(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, paramID := range attributes.ParameterIDs {
+		err = putParameterLink(ctx, id, paramID)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	author, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	// Create and persist the first Content revision
+	contents, err := cc.putContentRevision(ctx, id, 1, text, salt, owner.MSPID, author)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ba := Requirement{}
+	ba.DocType = requirementDocType
+	ba.ID = id
+	ba.Owner = owner
+	ba.Attributes = attributes
+	ba.ContentID = contents.ID
+	ba.Revision = contents.Revision
+	ba.setInitialStatus()
+
+	// Get the time at creating the asset in World state, every endorser sees the same transaction timestamp
+	ba.CreateTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to JSON
+	baBytes, _ := json.Marshal(ba)
+
+	// Commit to the ledger
+	err = putRequirementState(ctx, id, []byte(baBytes))
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the asset to the world state")
+	}
+
+	// Only the owning organization's peers can endorse changes until the asset is shared
+	err = setEndorsementPolicy(ctx, &ba)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ba, nil
+}
+
+// ShareAsset shares asset with a supplier by changing status and copying the current text into their shared
+// collection. The text of the current revision is passed in the transient map as returned by ReadContentText.
+func (cc *OEMContract) ShareAsset(ctx contractapi.TransactionContextInterface, assetID string,
+	supplierMSP string) ([]string, error) {
+
+	contents, err := transientContents(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, text, err := cc.checkShare(ctx, assetID, supplierMSP, contents)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = shareRequirement(ctx, req, text, supplierMSP)
+
+	if err != nil {
+		return nil, err
+	}
+
+	depIDs, err := cc.GetDependents(ctx, assetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.AssetShared, assetID,
+		events.ShareAssetPayload{ShareTime: req.ShareTime, Supplier: supplierMSP, Dependents: depIDs})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Return dependents for shared assets
+	return depIDs, nil
+}
+
+// checkShare verifies the caller can share the asset with the supplier and returns it, already moved to shared, with
+// the text to copy taken from contents. Nothing is written.
+func (cc *OEMContract) checkShare(ctx contractapi.TransactionContextInterface, assetID string,
+	supplierMSP string, contents map[string]*ContentText) (*Requirement, *ContentText, error) {
+
+	// Get the current asset
+	existing, err := getRequirementState(ctx, assetID)
+
+	if err != nil {
+		return nil, nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, nil, fmt.Errorf("Unable to find asset with id %s", assetID)
+	}
+
+	// convert to the BasicAsset
+	req := new(Requirement)
+	json.Unmarshal(existing, req)
+
+	// Only the owning organization can share
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if supplierMSP == req.Owner.MSPID || req.isSharedWith(supplierMSP) {
+		return nil, nil, fmt.Errorf("Asset with id %s is already shared with %s", assetID, supplierMSP)
+	}
+
+	// Only approved requirements can be shared, shared ones can be shared with further suppliers once their current
+	// revision is approved
+	if req.Status != StatusShared {
+		err = req.transitionTo(StatusShared)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if req.ApprovedRevision != req.Revision {
+		return nil, nil, fmt.Errorf("Revision %d of asset %s must be approved before it is shared further",
+			req.Revision, assetID)
+	}
+
+	// The suppliers already sharing the requirement endorse as well and cannot read the owner's collection
+	text, err := cc.verifiedText(ctx, contents, req.ID, req.Revision)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return req, text, nil
+}
+
+// shareRequirement copies the current text into the collection of the owner and the supplier, commits the checked
+// requirement and adds the supplier to its endorsement policy
+func shareRequirement(ctx contractapi.TransactionContextInterface, req *Requirement, text *ContentText,
+	supplierMSP string) error {
+
+	err := putContentText(ctx, sharedCollection(req.Owner.MSPID, supplierMSP), text)
+
+	if err != nil {
+		return err
+	}
+
+	req.SharedWith = append(req.SharedWith, supplierMSP)
+
+	// Get the time at sharing the asset in World state
+	req.ShareTime, err = txTimestamp(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// Commit back to ledger
+	baBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, req.ID, []byte(baBytes))
+
+	if err != nil {
+		return errors.New("Unable to update the world state")
+	}
+
+	return setEndorsementPolicy(ctx, req)
+}
+
+// CreateDependent records that each of toIDs depends on fromID, only the owning organization of fromID can add
+// dependents. Edges that already exist are kept, repeating the call changes nothing.
+func (cc *OEMContract) CreateDependent(ctx contractapi.TransactionContextInterface, fromID string,
+	toIDs []string) (*Requirement, error) {
+
+	// Get the start end
+	fromEnd, err := getRequirementState(ctx, fromID)
+
+	if err != nil {
+		return nil, errors.New("Unable to communicate with the World state")
+	}
+
+	if fromEnd == nil {
+		return nil, fmt.Errorf("Unable to find the asset with %s", fromID)
+	}
+
+	// load the start end
+	start := new(Requirement)
+	err = json.Unmarshal(fromEnd, start)
+
+	if err != nil {
+		return nil, errors.New("Unable to load start end from JSON")
+	}
+
+	// Only the owning organization can add dependents
+	err = authorizeOwner(ctx, start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Add an edge for every end, existing edges are kept
+	for _, toID := range toIDs {
+		exists, err := hasDependency(ctx, toID, fromID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			continue
+		}
+
+		err = cc.addDependency(ctx, toID, fromID)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return start, nil
+}
+
+// reviseContent writes newText by author as the next revision of the requirement, hands it to the suppliers and raises
+// assetModified with the summary of the changed lines. changeID names the accepted change request the text comes
+// from, if any.
+func (cc *OEMContract) reviseContent(ctx contractapi.TransactionContextInterface, ba *Requirement, newText string,
+	salt string, author string, changeID string, changes events.DiffSummary) error {
+
+	id := ba.ID
+
+	// The first revision of a migrated requirement replaces its public legacy text
+	if ba.Revision == 0 && ba.ContentID != "" && ctx.GetStub().DelState(ba.ContentID) != nil {
+		return errors.New("Unable to update the world state")
+	}
+
+	// Write the new text as the next revision, earlier revisions are never touched
+	content, err := cc.putContentRevision(ctx, id, ba.Revision+1, newText, salt, ba.Owner.MSPID, author)
+
+	if err != nil {
+		return err
+	}
+
+	// Suppliers the requirement is shared with receive the new text as well, so does an organization it was offered to
+	receivers := ba.SharedWith
+
+	if ba.PendingOwner != "" && !ba.isSharedWith(ba.PendingOwner) {
+		receivers = append(receivers, ba.PendingOwner)
+	}
+
+	for _, supplierMSP := range receivers {
+		err = putContentText(ctx, sharedCollection(ba.Owner.MSPID, supplierMSP),
+			&ContentText{ContentID: content.ID, ReqID: id, Revision: content.Revision, Text: newText, Salt: salt})
+
+		if err != nil {
+			return err
+		}
+
+		if supplierMSP == ba.PendingOwner && !ba.isSharedWith(supplierMSP) {
+			ba.OfferedRevisions = append(ba.OfferedRevisions, content.Revision)
+		}
+	}
+
+	// Point the requirement at the current revision
+	ba.ContentID = content.ID
+	ba.Revision = content.Revision
+
+	// The approval covered the previous text. A shared requirement keeps its suppliers until the owner has the new
+	// revision approved, the others go back to draft.
+	if ba.Status == StatusApproved || ba.Status == StatusInReview {
+		err = ba.transitionTo(StatusDraft)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	baBytes, _ := json.Marshal(ba)
+	err = putRequirementState(ctx, id, baBytes)
+
+	if err != nil {
+		return errors.New("Unable to update the world state")
+	}
+
+	// The new revision gets the same endorsement policy as the requirement
+	err = setEndorsementPolicy(ctx, ba)
+
+	if err != nil {
+		return err
+	}
+
+	// Get the dependents
+	depIDs, err := cc.GetDependents(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	// Collect everything affected further down the graph
+	impact, err := cc.GetImpactSet(ctx, id, 0)
+
+	if err != nil {
+		return err
+	}
+
+	// Every other organization owning an affected requirement has to acknowledge the change
+	pending, err := openAcknowledgements(ctx, ba, impact)
+
+	if err != nil {
+		return err
+	}
+
+	// Emit the event
+	return raiseEvent(ctx, events.AssetModified, ba.ID, events.DepEventPayload{Revision: ba.Revision,
+		ChangeID: changeID, Changes: changes, Dependents: depIDs,
+		Impact: impactPayload(impact), PendingAcks: pending})
+}
+
+// ReadAsset returns the basic asset with id given from the world state
+func (cc *OEMContract) ReadAsset(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
+	existing, err := getRequirementState(ctx, id)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Cannot read world state pair with key %s. Does not exist", id)
+	}
+
+	ba := new(Requirement)
+
+	err = json.Unmarshal(existing, ba)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", id)
+	}
+
+	// Only the owner and the suppliers it was shared with can read
+	mspID, err := authorizeReader(ctx, ba)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner reading its own asset leaves no receipt
+	if mspID == ba.Owner.MSPID {
+		return ba, nil
+	}
+
+	receipt, first, err := recordReadReceipt(ctx, id, mspID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Raise the event the first time each reader accesses this asset
+	if first {
+		// Emit the event
+		err = raiseEvent(ctx, events.AssetAccessed, ba.ID,
+			events.ReadAssetPayload{ShareTime: ba.ShareTime, ReadTime: receipt.FirstRead, ReaderMSP: mspID})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ba, nil
+}
+
+// GetAsset returns the basic asset with id given from the world state
+func (cc *OEMContract) GetAsset(ctx contractapi.TransactionContextInterface, id string) (*Requirement, error) {
+	existing, err := getRequirementState(ctx, id)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Cannot read world state pair with key %s. Does not exist", id)
+	}
+
+	ba := new(Requirement)
+
+	err = json.Unmarshal(existing, ba)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", id)
+	}
+
+	return ba, nil
+}
+
+// GetEvaluateTransactions returns functions of ComplexContract not to be tagged as submit
+func (cc *OEMContract) GetEvaluateTransactions() []string {
+	return []string{"GetAsset", "GetContentHistory", "GetContentRevision", "GetDependents", "GetDependencies",
+		"GetImpactSet", "QueryRequirements", "ReadContentText", "VerifyContentHash", "GetAlerts",
+		"GetRequirementSchema", "GetLinkedParameters", "GetParameterRequirements",
+		"GetApproval", "GetEndorsementPolicy", "GetReadReceipts",
+		"GetOutstandingAcknowledgements", "GetContentDiff",
+		"GetBaseline", "CompareBaselines", "GetChildren", "GetRequirementTree",
+		"GetTraceabilityMatrix", "ExportTraceabilityMatrix", "GetComments",
+		"GetChangeRequest", "GetChangeRequests", "GetChaincodeConfig"}
+}
+
+// txTimestamp returns the transaction timestamp as Unix seconds
+func txTimestamp(ctx contractapi.TransactionContextInterface) (string, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+
+	if err != nil {
+		return "", errors.New("Unable to read the transaction timestamp")
+	}
+
+	return strconv.FormatInt(ts.Seconds, 10), nil
+}
+
+func main() {
+	oemContract := new(OEMContract)
+
+	cc, err := contractapi.NewChaincode(oemContract)
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if err := cc.Start(); err != nil {
+		panic(err.Error())
+	}
+}
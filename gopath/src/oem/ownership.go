@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// OfferOwnership offers a requirement to another organization, nothing changes hands until it accepts. Every revision
+// the receiver does not hold yet is copied into the collection of both organizations so it can take the text over,
+// the texts of all revisions are passed in the transient map.
+func (cc *OEMContract) OfferOwnership(ctx contractapi.TransactionContextInterface, reqID string,
+	targetMSP string) (*Requirement, error) {
+
+	contents, err := transientContents(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if targetMSP == "" || targetMSP == req.Owner.MSPID {
+		return nil, fmt.Errorf("Asset %s cannot be offered to %s", reqID, targetMSP)
+	}
+
+	// A new offer replaces an earlier one
+	err = withdrawOffer(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	offered, err := cc.copyMissingRevisions(ctx, req, contents, sharedCollection(req.Owner.MSPID, targetMSP))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.PendingOwner = targetMSP
+	req.OfferedRevisions = offered
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, req.ID, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.OwnershipOffered, reqID,
+		events.OwnershipPayload{From: req.Owner.MSPID, To: targetMSP})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// AcceptOwnership makes the caller's organization the owner of a requirement offered to it. The previous owner keeps
+// read access like a supplier and the endorsement policy moves to the new owner. The texts of all revisions are
+// passed in the transient map as the new owner reads them with ReadContentText.
+func (cc *OEMContract) AcceptOwnership(ctx contractapi.TransactionContextInterface,
+	reqID string) (*Requirement, error) {
+
+	contents, err := transientContents(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := callerOwner(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PendingOwner == "" || owner.MSPID != req.PendingOwner {
+		return nil, fmt.Errorf("Asset %s was not offered to %s", reqID, owner.MSPID)
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	from := req.Owner.MSPID
+
+	// The new owner keeps every revision in its private collection
+	err = cc.copyRevisions(ctx, req, contents, privateCollection(owner.MSPID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous owner already holds the text in the collection shared with the new owner
+	sharedWith := []string{from}
+
+	for _, supplierMSP := range req.SharedWith {
+		if supplierMSP == owner.MSPID {
+			continue
+		}
+
+		// Suppliers read from the collection they share with the owner, which is now a different one
+		err = cc.copyRevisions(ctx, req, contents, sharedCollection(owner.MSPID, supplierMSP))
+
+		if err != nil {
+			return nil, err
+		}
+
+		sharedWith = append(sharedWith, supplierMSP)
+	}
+
+	// Pending changes and open threads keep their texts in the collections of the previous owner, which the new owner
+	// cannot read. They are closed, proposers and suppliers raise them again with the new owner.
+	transferTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = cc.expireChanges(ctx, reqID, "", transferTime,
+		fmt.Sprintf("Ownership of asset %s moved from %s to %s", reqID, from, owner.MSPID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedBy, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	err = resolveThreads(ctx, reqID, from, acceptedBy, transferTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Owner = owner
+	req.PendingOwner = ""
+	req.OfferedRevisions = nil
+	req.SharedWith = sharedWith
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, req.ID, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	err = setEndorsementPolicy(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.OwnershipTransferred, reqID,
+		events.OwnershipPayload{From: from, To: owner.MSPID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// DeclineOwnership refuses an offer, the owner can call it as well to withdraw its offer. The revisions copied for the
+// offer are removed from the collection of both organizations.
+func (cc *OEMContract) DeclineOwnership(ctx contractapi.TransactionContextInterface,
+	reqID string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PendingOwner == "" {
+		return nil, fmt.Errorf("Asset %s has no pending ownership offer", reqID)
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	if mspID != req.PendingOwner && mspID != req.Owner.MSPID {
+		return nil, &AuthorizationError{ID: reqID, CallerMSP: mspID, OwnerMSP: req.Owner.MSPID}
+	}
+
+	target := req.PendingOwner
+
+	err = withdrawOffer(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, req.ID, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, events.OwnershipDeclined, reqID, events.OwnershipPayload{From: req.Owner.MSPID, To: target})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// authorizePendingOwner fails unless the requirement was offered to the caller's organization
+func authorizePendingOwner(ctx contractapi.TransactionContextInterface, req *Requirement) (string, error) {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+
+	if err != nil {
+		return "", errors.New("Unable to read the MSP ID of the client identity")
+	}
+
+	if req.PendingOwner == "" || mspID != req.PendingOwner {
+		return "", &AccessError{ID: req.ID, CallerMSP: mspID}
+	}
+
+	return mspID, nil
+}
+
+// withdrawOffer removes the revisions copied for the pending offer, if any, and clears it
+func withdrawOffer(ctx contractapi.TransactionContextInterface, req *Requirement) error {
+	if req.PendingOwner == "" {
+		return nil
+	}
+
+	collection := sharedCollection(req.Owner.MSPID, req.PendingOwner)
+
+	for _, rev := range req.OfferedRevisions {
+		key, _ := contentKey(ctx, req.ID, rev)
+
+		if ctx.GetStub().DelPrivateData(collection, key) != nil {
+			return fmt.Errorf("Unable to remove the requirement text from collection %s", collection)
+		}
+	}
+
+	req.PendingOwner = ""
+	req.OfferedRevisions = nil
+
+	return nil
+}
+
+// copyMissingRevisions copies the revisions the target collection does not hold yet from contents and returns them.
+// The endorsing suppliers cannot read the owner's collections, the texts come from the client and are checked against
+// their hashes.
+func (cc *OEMContract) copyMissingRevisions(ctx contractapi.TransactionContextInterface, req *Requirement,
+	contents map[string]*ContentText, to string) ([]int, error) {
+
+	copied := []int{}
+
+	for rev := 1; rev <= req.Revision; rev++ {
+		key, _ := contentKey(ctx, req.ID, rev)
+
+		exists, err := holdsText(ctx, to, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			continue
+		}
+
+		text, err := cc.verifiedText(ctx, contents, req.ID, rev)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = putContentText(ctx, to, text)
+
+		if err != nil {
+			return nil, err
+		}
+
+		copied = append(copied, rev)
+	}
+
+	return copied, nil
+}
+
+// copyRevisions copies the text of every revision of the requirement from contents into a collection
+func (cc *OEMContract) copyRevisions(ctx contractapi.TransactionContextInterface, req *Requirement,
+	contents map[string]*ContentText, to string) error {
+
+	for rev := 1; rev <= req.Revision; rev++ {
+		text, err := cc.verifiedText(ctx, contents, req.ID, rev)
+
+		if err != nil {
+			return err
+		}
+
+		err = putContentText(ctx, to, text)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// offer offers requirement id to targetMSP
+func (n *testNetwork) offer(mspID string, id string, targetMSP string) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, n.contents(mspID, id), func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.OfferOwnership(ctx, id, targetMSP)
+		return err
+	})
+}
+
+// decline declines or withdraws the pending offer of requirement id
+func (n *testNetwork) decline(mspID string, id string) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.DeclineOwnership(ctx, id)
+		return err
+	})
+}
+
+// holdsText reports whether revision rev of requirement id is in the collection of ownerMSP and mspID
+func (n *testNetwork) holdsText(ownerMSP string, mspID string, id string, rev int) bool {
+	n.t.Helper()
+
+	var text []byte
+
+	n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) error {
+		key, err := contentKey(ctx, id, rev)
+
+		if err != nil {
+			return err
+		}
+
+		text, err = ctx.GetStub().GetPrivateData(sharedCollection(ownerMSP, mspID), key)
+		return err
+	})
+
+	return text != nil
+}
+
+func TestDeclinedOfferRemovesText(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.offer(orgRequirements, "REQ-1", orgDesign)
+
+	if !n.holdsText(orgRequirements, orgDesign, "REQ-1", 1) {
+		t.Fatal("the offered text was not copied to the receiver")
+	}
+
+	n.decline(orgDesign, "REQ-1")
+
+	if n.holdsText(orgRequirements, orgDesign, "REQ-1", 1) {
+		t.Fatal("the text stays with the organization that declined the offer")
+	}
+
+	if req := n.requirement("REQ-1"); req.PendingOwner != "" || len(req.OfferedRevisions) != 0 {
+		t.Fatalf("the offer was not cleared: %+v", req)
+	}
+}
+
+func TestWithdrawnOfferRemovesText(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.offer(orgRequirements, "REQ-1", orgDesign)
+
+	// A revision made while the offer is pending goes to the receiver as well
+	change := n.propose(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 36 m")
+	n.accept(orgRequirements, "REQ-1", change)
+
+	if req := n.requirement("REQ-1"); len(req.OfferedRevisions) != 2 {
+		t.Fatalf("expected both revisions to be offered, got %v", req.OfferedRevisions)
+	}
+
+	// Offering to another organization withdraws the first offer
+	n.offer(orgRequirements, "REQ-1", orgSupplier)
+
+	if n.holdsText(orgRequirements, orgDesign, "REQ-1", 1) || n.holdsText(orgRequirements, orgDesign, "REQ-1", 2) {
+		t.Fatal("the text stays with the organization of the replaced offer")
+	}
+
+	n.decline(orgRequirements, "REQ-1")
+
+	if n.holdsText(orgRequirements, orgSupplier, "REQ-1", 1) || n.holdsText(orgRequirements, orgSupplier, "REQ-1", 2) {
+		t.Fatal("the text stays with the organization of the withdrawn offer")
+	}
+}
+
+func TestDeclinedOfferKeepsSupplierText(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.offer(orgRequirements, "REQ-1", orgSupplier)
+
+	if req := n.requirement("REQ-1"); len(req.OfferedRevisions) != 0 {
+		t.Fatalf("the supplier already holds the text, nothing should be copied: %v", req.OfferedRevisions)
+	}
+
+	n.decline(orgSupplier, "REQ-1")
+
+	if !n.holdsText(orgRequirements, orgSupplier, "REQ-1", 1) {
+		t.Fatal("the supplier lost the shared text")
+	}
+}
+
+func TestAcceptOwnershipWithSuppliers(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+	n.share(orgRequirements, "REQ-1", orgSimulation)
+	n.offer(orgRequirements, "REQ-1", orgDesign)
+
+	n.mustSubmit(orgDesign, n.contents(orgDesign, "REQ-1"), func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcceptOwnership(ctx, "REQ-1")
+		return err
+	})
+
+	req := n.requirement("REQ-1")
+
+	if req.Owner.MSPID != orgDesign || req.PendingOwner != "" || len(req.OfferedRevisions) != 0 {
+		t.Fatalf("the ownership was not transferred: %+v", req)
+	}
+
+	// The previous owner reads the requirement like a supplier and endorses with the other suppliers
+	if orgs := n.policy("REQ-1"); !reflect.DeepEqual(orgs, []string{orgDesign, orgRequirements, orgSimulation,
+		orgSupplier}) {
+		t.Fatalf("the new owner and every supplier should endorse, the policy lists %v", orgs)
+	}
+
+	for _, mspID := range []string{orgRequirements, orgSupplier, orgSimulation} {
+		if !n.holdsText(orgDesign, mspID, "REQ-1", 1) {
+			t.Fatalf("%s did not receive the text from the new owner", mspID)
+		}
+	}
+}
+
+func TestAcceptOwnershipClosesChangesAndThreads(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+
+	stale := n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 38 m")
+
+	var thread *Comment
+
+	n.mustSubmit(orgSupplier, withText("Is the test done on a wet surface?"),
+		func(ctx contractapi.TransactionContextInterface) (err error) {
+			thread, err = n.cc.AddComment(ctx, "REQ-1", "")
+			return err
+		})
+
+	n.offer(orgRequirements, "REQ-1", orgDesign)
+
+	n.mustSubmit(orgDesign, n.contents(orgDesign, "REQ-1"), func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcceptOwnership(ctx, "REQ-1")
+		return err
+	})
+
+	// The new owner cannot read the texts kept with the previous owner, both are closed
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		change, err := n.cc.GetChangeRequest(ctx, "REQ-1", stale.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if change.Status != changeExpired {
+			t.Fatalf("the change proposed to the previous owner stays %s", change.Status)
+		}
+
+		closed, err := getComment(ctx, "REQ-1", thread.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if !closed.Resolved {
+			t.Fatal("the thread with the previous owner stays open")
+		}
+
+		return nil
+	})
+
+	// The supplier raises them again with the new owner
+	change := n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 38 m")
+	n.accept(orgDesign, "REQ-1", change)
+
+	if req := n.requirement("REQ-1"); req.Revision != 2 {
+		t.Fatalf("the new owner could not accept the change, the asset is at revision %d", req.Revision)
+	}
+
+	n.mustSubmit(orgSupplier, withText("Is the test done on a wet surface?"),
+		func(ctx contractapi.TransactionContextInterface) (err error) {
+			thread, err = n.cc.AddComment(ctx, "REQ-1", "")
+			return err
+		})
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		page, err := n.cc.GetComments(ctx, "REQ-1", 10, "")
+
+		if err != nil {
+			return err
+		}
+
+		if latest := page.Comments[len(page.Comments)-1]; latest.ID != thread.ID || latest.Text == "" {
+			t.Fatalf("the new owner does not read the new thread: %+v", latest)
+		}
+
+		return nil
+	})
+}
+
+func TestRetireWithdrawsOffer(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.offer(orgRequirements, "REQ-1", orgDesign)
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RetireRequirement(ctx, "REQ-1", "Superseded by the new braking regulation")
+		return err
+	})
+
+	if req := n.requirement("REQ-1"); req.PendingOwner != "" || len(req.OfferedRevisions) != 0 {
+		t.Fatalf("the offer of a retired requirement stays pending: %+v", req)
+	}
+
+	if n.holdsText(orgRequirements, orgDesign, "REQ-1", 1) {
+		t.Fatal("the text stays with the organization the retired requirement was offered to")
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Parameter is the engineering parameter held by the paramnet chaincode
+type Parameter struct {
+	ParamID       string  `json:"id"`
+	Name          string  `json:"name"`
+	MinValue      float32 `json:"min"`
+	MaxValue      float32 `json:"max"`
+	GoalValue     float32 `json:"goal"`
+	ReleaseStatus string  `json:"status"`
+}
+
+// LinkParameter links requirement reqID to a parameter that must exist in the paramnet chaincode
+func (cc *OEMContract) LinkParameter(ctx contractapi.TransactionContextInterface, reqID string,
+	paramID string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.checkOpen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contains(req.Attributes.ParameterIDs, paramID) {
+		return nil, fmt.Errorf("Requirement %s is already linked to parameter %s", reqID, paramID)
+	}
+
+	err = indexParameter(ctx, reqID, paramID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Attributes.ParameterIDs = append(req.Attributes.ParameterIDs, paramID)
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, reqID, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	return req, nil
+}
+
+// GetLinkedParameters returns the parameters linked to requirement reqID as currently held by paramnet
+func (cc *OEMContract) GetLinkedParameters(ctx contractapi.TransactionContextInterface,
+	reqID string) ([]*Parameter, error) {
+
+	req, err := cc.GetAsset(ctx, reqID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	params := []*Parameter{}
+
+	for _, paramID := range req.Attributes.ParameterIDs {
+		param, err := fetchParameter(ctx, paramID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// GetParameterRequirements returns the IDs of the requirements linked to parameter paramID
+func (cc *OEMContract) GetParameterRequirements(ctx contractapi.TransactionContextInterface,
+	paramID string) ([]string, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(parameterLinkObjectType, []string{paramID})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	ids := []string{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read parameter links from the world state")
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+
+		if err != nil || len(attributes) != 2 {
+			return nil, fmt.Errorf("Malformed parameter link key %s", kv.Key)
+		}
+
+		ids = append(ids, attributes[1])
+	}
+
+	return ids, nil
+}
+
+// indexParameter checks the parameter exists in paramnet and records the link from the parameter side
+func indexParameter(ctx contractapi.TransactionContextInterface, reqID string, paramID string) error {
+
+	_, err := fetchParameter(ctx, paramID)
+
+	if err != nil {
+		return err
+	}
+
+	return putParameterLink(ctx, reqID, paramID)
+}
+
+// putParameterLink records the link from the parameter side
+func putParameterLink(ctx contractapi.TransactionContextInterface, reqID string, paramID string) error {
+
+	key, _ := ctx.GetStub().CreateCompositeKey(parameterLinkObjectType, []string{paramID, reqID})
+
+	err := ctx.GetStub().PutState(key, []byte(reqID))
+
+	if err != nil {
+		return errors.New("Unable to commit the parameter link to the world state")
+	}
+
+	return nil
+}
+
+// fetchParameter reads a parameter from the paramnet chaincode named in the chaincode configuration
+func fetchParameter(ctx contractapi.TransactionContextInterface, paramID string) (*Parameter, error) {
+
+	config, err := getChaincodeConfig(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := [][]byte{[]byte("GetParameter"), []byte(paramID)}
+	response := ctx.GetStub().InvokeChaincode(config.ParamChaincode, args, "")
+
+	if response.Status != shim.OK {
+		return nil, fmt.Errorf("Parameter %s could not be read from %s: %s", paramID, config.ParamChaincode,
+			response.Message)
+	}
+
+	param := new(Parameter)
+	err = json.Unmarshal(response.Payload, param)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data returned by %s for parameter %s was not of type Parameter",
+			config.ParamChaincode, paramID)
+	}
+
+	return param, nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Transient map entries carrying requirement text, texts holds a JSON object of texts keyed by asset id, contents a
+// JSON array of revision texts as returned by ReadContentText and salt the random value the client generates for
+// every transaction writing text
+const (
+	transientTextKey     = "text"
+	transientTextsKey    = "texts"
+	transientContentsKey = "contents"
+	transientSaltKey     = "salt"
+)
+
+// minSaltLength is the shortest salt accepted, 16 random bytes in hex
+const minSaltLength = 32
+
+// privateCollection is the collection readable only by the given organization, see collections_config.json
+func privateCollection(mspID string) string {
+	return mspID + "PrivateCollection"
+}
+
+// sharedCollection is the collection readable by exactly the two given organizations
+func sharedCollection(mspA string, mspB string) string {
+	orgs := []string{mspA, mspB}
+	sort.Strings(orgs)
+
+	return orgs[0] + orgs[1] + "SharedCollection"
+}
+
+// contentHash is the public fingerprint of a requirement text. The salt is kept next to the text in the private
+// collections, without it a guessed text cannot be confirmed against the hash.
+func contentHash(salt string, text string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + text))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// itemSalt derives the salt of one of several texts written with the same transaction salt
+func itemSalt(salt string, id string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + id))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// transientText reads the requirement text and its salt from the transient map so they never reach the public ledger
+func transientText(ctx contractapi.TransactionContextInterface) (string, string, error) {
+	transient, err := ctx.GetStub().GetTransient()
+
+	if err != nil {
+		return "", "", errors.New("Unable to read the transient map")
+	}
+
+	text, ok := transient[transientTextKey]
+
+	if !ok {
+		return "", "", fmt.Errorf("The requirement text must be passed in the transient map under %s",
+			transientTextKey)
+	}
+
+	salt, err := transientSalt(transient)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(text), salt, nil
+}
+
+// transientTexts reads the texts of a bulk creation and the salt they are derived from from the transient map
+func transientTexts(ctx contractapi.TransactionContextInterface) (map[string]string, string, error) {
+	transient, err := ctx.GetStub().GetTransient()
+
+	if err != nil {
+		return nil, "", errors.New("Unable to read the transient map")
+	}
+
+	textsBytes, ok := transient[transientTextsKey]
+
+	if !ok {
+		return nil, "", fmt.Errorf("The requirement texts must be passed in the transient map under %s",
+			transientTextsKey)
+	}
+
+	texts := map[string]string{}
+	err = json.Unmarshal(textsBytes, &texts)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("The transient map entry %s is not a JSON object of texts keyed by asset id",
+			transientTextsKey)
+	}
+
+	salt, err := transientSalt(transient)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return texts, salt, nil
+}
+
+// transientContents reads the revision texts passed in the transient map keyed by content id. Transactions writing
+// keys the suppliers endorse take existing texts from the client, the peers of the suppliers cannot read the owner's
+// collections.
+func transientContents(ctx contractapi.TransactionContextInterface) (map[string]*ContentText, error) {
+	transient, err := ctx.GetStub().GetTransient()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the transient map")
+	}
+
+	contentsBytes, ok := transient[transientContentsKey]
+
+	if !ok {
+		return nil, fmt.Errorf("The requirement texts must be passed in the transient map under %s",
+			transientContentsKey)
+	}
+
+	list := []*ContentText{}
+	err = json.Unmarshal(contentsBytes, &list)
+
+	if err != nil {
+		return nil, fmt.Errorf("The transient map entry %s is not a JSON array of revision texts",
+			transientContentsKey)
+	}
+
+	contents := map[string]*ContentText{}
+
+	for _, text := range list {
+		contents[text.ContentID] = text
+	}
+
+	return contents, nil
+}
+
+// verifiedText returns the text of a revision passed by the client after checking it against the public hash
+func (cc *OEMContract) verifiedText(ctx contractapi.TransactionContextInterface, contents map[string]*ContentText,
+	reqID string, rev int) (*ContentText, error) {
+
+	content, err := cc.GetContentRevision(ctx, reqID, rev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text, ok := contents[content.ID]
+
+	if !ok {
+		return nil, fmt.Errorf("No text was passed for revision %d of asset with id %s", rev, reqID)
+	}
+
+	if text.ReqID != reqID || text.Revision != rev || contentHash(text.Salt, text.Text) != content.Hash {
+		return nil, fmt.Errorf("The text passed for revision %d of asset with id %s does not match its hash", rev,
+			reqID)
+	}
+
+	return text, nil
+}
+
+// holdsText reports whether a collection holds the text of a revision. Only the hash is read, which every peer
+// keeps, so organizations outside the collection can still endorse.
+func holdsText(ctx contractapi.TransactionContextInterface, collection string, contentID string) (bool, error) {
+	hash, err := ctx.GetStub().GetPrivateDataHash(collection, contentID)
+
+	if err != nil {
+		return false, fmt.Errorf("Unable to read from collection %s", collection)
+	}
+
+	return hash != nil, nil
+}
+
+// transientSalt checks the salt passed in the transient map, it must be random hex generated by the client because
+// endorsing peers cannot agree on a random value
+func transientSalt(transient map[string][]byte) (string, error) {
+	salt, ok := transient[transientSaltKey]
+
+	if !ok {
+		return "", fmt.Errorf("A random salt must be passed in the transient map under %s", transientSaltKey)
+	}
+
+	_, err := hex.DecodeString(string(salt))
+
+	if err != nil || len(salt) < minSaltLength {
+		return "", fmt.Errorf("The salt must be at least %d hex digits", minSaltLength)
+	}
+
+	return string(salt), nil
+}
+
+// putContentText writes the text of a revision into a private data collection
+func putContentText(ctx contractapi.TransactionContextInterface, collection string, text *ContentText) error {
+	textBytes, _ := json.Marshal(text)
+
+	err := ctx.GetStub().PutPrivateData(collection, text.ContentID, textBytes)
+
+	if err != nil {
+		return fmt.Errorf("Unable to write the requirement text to collection %s", collection)
+	}
+
+	return nil
+}
+
+// getContentText reads the text of a revision from a private data collection
+func getContentText(ctx contractapi.TransactionContextInterface, collection string,
+	contentID string) (*ContentText, error) {
+
+	existing, err := ctx.GetStub().GetPrivateData(collection, contentID)
+
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read from collection %s", collection)
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Collection %s holds no text for content %s", collection, contentID)
+	}
+
+	text := new(ContentText)
+	err = json.Unmarshal(existing, text)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from collection %s was not of type ContentText", collection)
+	}
+
+	return text, nil
+}
+
+// ReadContentText returns the text of a revision from the collection the caller's organization can read. The
+// organization a requirement is offered to can read the revisions copied for the offer, approvers of a pending
+// approval request the revision under review.
+func (cc *OEMContract) ReadContentText(ctx contractapi.TransactionContextInterface, id string,
+	rev int) (*ContentText, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mspID, err := authorizeReader(ctx, req)
+
+	if err != nil {
+		mspID, err = authorizePendingOwner(ctx, req)
+	}
+
+	if err != nil {
+		mspID, err = cc.authorizeApprover(ctx, req, rev)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := cc.GetContentRevision(ctx, id, rev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	collection := privateCollection(mspID)
+
+	if mspID != req.Owner.MSPID {
+		collection = sharedCollection(req.Owner.MSPID, mspID)
+	}
+
+	return getContentText(ctx, collection, content.ID)
+}
+
+// VerifyContentHash checks the text and salt passed in the transient map against the public hash of a revision
+func (cc *OEMContract) VerifyContentHash(ctx contractapi.TransactionContextInterface, id string,
+	rev int) (bool, error) {
+
+	text, salt, err := transientText(ctx)
+
+	if err != nil {
+		return false, err
+	}
+
+	content, err := cc.GetContentRevision(ctx, id, rev)
+
+	if err != nil {
+		return false, err
+	}
+
+	return content.Hash == contentHash(salt, text), nil
+}
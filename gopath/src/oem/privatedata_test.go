@@ -0,0 +1,67 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestNewAssetRequiresSalt(t *testing.T) {
+	n := newTestNetwork(t)
+
+	for _, transient := range []map[string]string{
+		{transientTextKey: "The brake shall engage within 100 ms"},
+		{transientTextKey: "The brake shall engage within 100 ms", transientSaltKey: "abc"},
+		{transientTextKey: "The brake shall engage within 100 ms", transientSaltKey: testSalt[2:] + "zz"},
+	} {
+		n.mustFail(orgRequirements, transient, func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.NewAsset(ctx, "REQ-1", Attributes{})
+		})
+	}
+}
+
+func TestContentHashIsSalted(t *testing.T) {
+	n := newTestNetwork(t)
+	text := "The brake shall engage within 100 ms"
+	n.create(orgRequirements, "REQ-1", text)
+
+	var content *Content
+	var private *ContentText
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		content, err = n.cc.GetContentRevision(ctx, "REQ-1", 1)
+
+		if err != nil {
+			return err
+		}
+
+		private, err = n.cc.ReadContentText(ctx, "REQ-1", 1)
+		return err
+	})
+
+	unsalted := sha256.Sum256([]byte(text))
+
+	if content.Hash == hex.EncodeToString(unsalted[:]) {
+		t.Fatal("the public hash can be recomputed from the text alone")
+	}
+
+	if private.Salt != testSalt || private.Text != text {
+		t.Fatalf("the private text does not carry its salt: %+v", private)
+	}
+
+	for salt, expected := range map[string]bool{testSalt: true, itemSalt(testSalt, "REQ-1"): false} {
+		var verified bool
+
+		n.mustSubmit(orgRequirements, map[string]string{transientTextKey: text, transientSaltKey: salt},
+			func(ctx contractapi.TransactionContextInterface) (err error) {
+				verified, err = n.cc.VerifyContentHash(ctx, "REQ-1", 1)
+				return err
+			})
+
+		if verified != expected {
+			t.Fatalf("verifying with salt %s returned %t", salt, verified)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// requirementDocType marks requirement documents for CouchDB selectors
+const requirementDocType = "requirement"
+
+// RequirementFilter selects requirements in QueryRequirements, empty fields are ignored and times are inclusive
+type RequirementFilter struct {
+	OwnerMSP      string `json:"ownermsp" metadata:"ownermsp,optional"`
+	Status        string `json:"status" metadata:"status,optional"`
+	CreatedAfter  string `json:"createdafter" metadata:"createdafter,optional"`
+	CreatedBefore string `json:"createdbefore" metadata:"createdbefore,optional"`
+	SharedAfter   string `json:"sharedafter" metadata:"sharedafter,optional"`
+	SharedBefore  string `json:"sharedbefore" metadata:"sharedbefore,optional"`
+	PendingOwner  string `json:"pendingowner" metadata:"pendingowner,optional"`
+
+	// Accessed is true or false, AccessedBy narrows the reads that count to those of one organization
+	Accessed   string `json:"accessed" metadata:"accessed,optional"`
+	AccessedBy string `json:"accessedby" metadata:"accessedby,optional"`
+}
+
+// RequirementQueryResult is one page of QueryRequirements
+type RequirementQueryResult struct {
+	Requirements []*Requirement `json:"requirements"`
+	Bookmark     string         `json:"bookmark"`
+	FetchedCount int32          `json:"fetchedcount"`
+}
+
+// QueryRequirements returns a page of requirements matching the filter, pass the returned bookmark for the next page
+func (cc *OEMContract) QueryRequirements(ctx contractapi.TransactionContextInterface, filter RequirementFilter,
+	pageSize int32, bookmark string) (*RequirementQueryResult, error) {
+
+	selector, err := filter.selector()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Accessed != "" || filter.AccessedBy != "" {
+		err = filter.selectAccessed(ctx, selector)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	queryBytes, _ := json.Marshal(map[string]interface{}{"selector": selector})
+
+	iterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(string(queryBytes), pageSize, bookmark)
+
+	if err != nil {
+		return nil, errors.New("Unable to query the world state")
+	}
+
+	defer iterator.Close()
+
+	result := &RequirementQueryResult{Requirements: []*Requirement{}}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read query results from the world state")
+		}
+
+		req := new(Requirement)
+		err = json.Unmarshal(kv.Value, req)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", kv.Key)
+		}
+
+		result.Requirements = append(result.Requirements, req)
+	}
+
+	result.Bookmark = metadata.Bookmark
+	result.FetchedCount = metadata.FetchedRecordsCount
+
+	return result, nil
+}
+
+// selectRequirements loads the listed requirements, or queries the ones matching the filter. Rich query results are
+// not re-checked when a transaction commits, submit transactions must read the matches again by key.
+func (cc *OEMContract) selectRequirements(ctx contractapi.TransactionContextInterface, reqIDs []string,
+	filter RequirementFilter) ([]*Requirement, error) {
+
+	if len(reqIDs) > 0 && filter != (RequirementFilter{}) {
+		return nil, errors.New("Either requirement ids or a filter can be given, not both")
+	}
+
+	if len(reqIDs) > 0 {
+		return cc.loadRequirements(ctx, reqIDs)
+	}
+
+	selector, err := filter.selector()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Accessed != "" || filter.AccessedBy != "" {
+		err = filter.selectAccessed(ctx, selector)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	queryBytes, _ := json.Marshal(map[string]interface{}{"selector": selector})
+
+	iterator, err := ctx.GetStub().GetQueryResult(string(queryBytes))
+
+	if err != nil {
+		return nil, errors.New("Unable to query the world state")
+	}
+
+	defer iterator.Close()
+
+	reqs := []*Requirement{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read query results from the world state")
+		}
+
+		req := new(Requirement)
+		err = json.Unmarshal(kv.Value, req)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Requirement", kv.Key)
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	return reqs, nil
+}
+
+// selector builds the CouchDB selector for the filter, the fields match the indexes in META-INF
+func (filter *RequirementFilter) selector() (map[string]interface{}, error) {
+	selector := map[string]interface{}{"docType": requirementDocType}
+
+	if filter.OwnerMSP != "" {
+		selector["owner.mspid"] = filter.OwnerMSP
+	}
+
+	if filter.Status != "" {
+		selector["status"] = filter.Status
+	}
+
+	if filter.PendingOwner != "" {
+		selector["pendingowner"] = filter.PendingOwner
+	}
+
+	if r := timeRange(filter.CreatedAfter, filter.CreatedBefore); r != nil {
+		selector["createtime"] = r
+	}
+
+	if r := timeRange(filter.SharedAfter, filter.SharedBefore); r != nil {
+		selector["sharetime"] = r
+	}
+
+	switch filter.Accessed {
+	case "", "true", "false":
+	default:
+		return nil, fmt.Errorf("Accessed filter must be true or false, got %s", filter.Accessed)
+	}
+
+	return selector, nil
+}
+
+// selectAccessed restricts the selector to the requirements with, or without, read receipts. Receipts are
+// documents of their own, so the requirements they name are collected first.
+func (filter *RequirementFilter) selectAccessed(ctx contractapi.TransactionContextInterface,
+	selector map[string]interface{}) error {
+
+	receiptSelector := map[string]interface{}{"docType": receiptDocType}
+
+	if filter.AccessedBy != "" {
+		receiptSelector["mspid"] = filter.AccessedBy
+	}
+
+	queryBytes, _ := json.Marshal(map[string]interface{}{"selector": receiptSelector, "fields": []string{"reqid"}})
+
+	iterator, err := ctx.GetStub().GetQueryResult(string(queryBytes))
+
+	if err != nil {
+		return errors.New("Unable to query the world state")
+	}
+
+	defer iterator.Close()
+
+	ids := []string{}
+	seen := map[string]bool{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return errors.New("Unable to read query results from the world state")
+		}
+
+		receipt := new(ReadReceipt)
+		err = json.Unmarshal(kv.Value, receipt)
+
+		if err != nil {
+			return fmt.Errorf("Data retrieved from world state for key %s was not of type ReadReceipt", kv.Key)
+		}
+
+		if !seen[receipt.ReqID] {
+			seen[receipt.ReqID] = true
+			ids = append(ids, receipt.ReqID)
+		}
+	}
+
+	if filter.Accessed == "false" {
+		selector["id"] = map[string]interface{}{"$nin": ids}
+	} else {
+		selector["id"] = map[string]interface{}{"$in": ids}
+	}
+
+	return nil
+}
+
+// timeRange returns an inclusive range condition, times are Unix seconds so string order is time order
+func timeRange(after string, before string) map[string]string {
+	if after == "" && before == "" {
+		return nil
+	}
+
+	r := map[string]string{}
+
+	if after != "" {
+		r["$gte"] = after
+	}
+
+	if before != "" {
+		r["$lte"] = before
+	}
+
+	return r
+}
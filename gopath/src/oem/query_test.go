@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// query returns the IDs of the requirements matching filter
+func (n *testNetwork) query(filter RequirementFilter) []string {
+	n.t.Helper()
+
+	var result *RequirementQueryResult
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		result, err = n.cc.QueryRequirements(ctx, filter, 0, "")
+		return err
+	})
+
+	ids := []string{}
+
+	for _, req := range result.Requirements {
+		ids = append(ids, req.ID)
+	}
+
+	return ids
+}
+
+func TestQueryByReadReceipts(t *testing.T) {
+	n := newTestNetwork(t)
+
+	for _, id := range []string{"REQ-1", "REQ-2", "REQ-3"} {
+		n.create(orgRequirements, id, "The vehicle shall stop from 100 km/h within 40 m")
+	}
+
+	for _, id := range []string{"REQ-1", "REQ-2"} {
+		n.approve(orgRequirements, id, orgSimulation)
+		n.share(orgRequirements, id, orgSupplier)
+		n.share(orgRequirements, id, orgDesign)
+	}
+
+	for mspID, id := range map[string]string{orgSupplier: "REQ-1", orgDesign: "REQ-2"} {
+		n.mustSubmit(mspID, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.ReadAsset(ctx, id)
+			return err
+		})
+	}
+
+	for _, tc := range []struct {
+		filter   RequirementFilter
+		expected []string
+	}{
+		{RequirementFilter{Accessed: "true"}, []string{"REQ-1", "REQ-2"}},
+		{RequirementFilter{Accessed: "false"}, []string{"REQ-3"}},
+		{RequirementFilter{AccessedBy: orgSupplier}, []string{"REQ-1"}},
+		{RequirementFilter{Accessed: "false", AccessedBy: orgSupplier}, []string{"REQ-2", "REQ-3"}},
+		{RequirementFilter{Accessed: "true", Status: StatusShared, AccessedBy: orgDesign}, []string{"REQ-2"}},
+	} {
+		if ids := n.query(tc.filter); !reflect.DeepEqual(ids, tc.expected) {
+			t.Fatalf("filter %+v returned %v, expected %v", tc.filter, ids, tc.expected)
+		}
+	}
+
+	n.mustFail(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.QueryRequirements(ctx, RequirementFilter{Accessed: "yes"}, 0, "")
+		return err
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// receiptDocType marks read receipt documents for CouchDB selectors
+const receiptDocType = "receipt"
+
+// ReadReceipt records when one client of an organization first and last read a requirement
+type ReadReceipt struct {
+	DocType   string `json:"docType"`
+	ReqID     string `json:"reqid"`
+	MSPID     string `json:"mspid"`
+	Identity  string `json:"identity"`
+	FirstRead string `json:"firstread"`
+	LastRead  string `json:"lastread"`
+}
+
+// GetReadReceipts returns the read receipts of every organization the requirement was shared with
+func (cc *OEMContract) GetReadReceipts(ctx contractapi.TransactionContextInterface, id string) ([]*ReadReceipt, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Suppliers do not learn which other suppliers read the requirement
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(receiptObjectType, []string{id})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	receipts := []*ReadReceipt{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read receipts from the world state")
+		}
+
+		receipt := new(ReadReceipt)
+		err = json.Unmarshal(kv.Value, receipt)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type ReadReceipt", kv.Key)
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+// recordReadReceipt stores the read by the calling client and reports whether it was its first one
+func recordReadReceipt(ctx contractapi.TransactionContextInterface, id string,
+	mspID string) (*ReadReceipt, bool, error) {
+
+	identity, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, false, errors.New("Unable to read the client identity")
+	}
+
+	readTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	key, _ := ctx.GetStub().CreateCompositeKey(receiptObjectType, []string{id, mspID, identity})
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, false, errors.New("Unable to interact with the world state")
+	}
+
+	receipt := &ReadReceipt{DocType: receiptDocType, ReqID: id, MSPID: mspID, Identity: identity, FirstRead: readTime}
+	first := existing == nil
+
+	if !first {
+		err = json.Unmarshal(existing, receipt)
+
+		if err != nil {
+			return nil, false, fmt.Errorf("Data retrieved from world state for key %s was not of type ReadReceipt", key)
+		}
+	}
+
+	receipt.LastRead = readTime
+
+	receiptBytes, _ := json.Marshal(receipt)
+	err = ctx.GetStub().PutState(key, receiptBytes)
+
+	if err != nil {
+		return nil, false, errors.New("Unable to commit the read receipt to the world state")
+	}
+
+	return receipt, first, nil
+}
@@ -1,42 +1,112 @@
-package main
-
-// Owner contains the full name of the asset owner
-type Owner struct {
-	FirstName string `json:"firstname"`
-	LastName  string `json:"lastname"`
-}
-
-// Content is the text
-type Content struct {
-	ID   string `json:"contentid"`
-	Text string `json:"text"`
-}
-
-//Dependents store dependent information
-type Dependents struct {
-	ID     string   `json:"depid"`
-	DepIDs []string `json:"depids"`
-}
-
-// Requirement an asset
-type Requirement struct {
-	ID         string `json:"id"`
-	Owner      Owner  `json:"owner"`
-	ContentID  string `json:"contentid"`
-	Status     string `json:"status"`
-	CreateTime string `createtime:"createtime"`
-	ShareTime  string `json:"sharetime"`
-	AccessTime string `json:"accesstime"`
-	DepID      string `json:"depid"`
-	IsAccessed bool   `json:"isaccessed"`
-}
-
-// SetConditionUsed marks the asses as used
-func (req *Requirement) setStatusShared() {
-	req.Status = "shared"
-}
-
-// Set the status to created on initializing
-func (req *Requirement) setInitialStatus() {
-	req.Status = "created"
-}
+package main
+
+// Owner identifies the organization and certificate that created the asset
+type Owner struct {
+	MSPID      string            `json:"mspid"`
+	Subject    string            `json:"subject"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Content is one immutable revision of the requirement text, only the hash of the text is public
+type Content struct {
+	ID        string `json:"contentid"`
+	ReqID     string `json:"reqid"`
+	Revision  int    `json:"revision"`
+	Hash      string `json:"hash"`
+	Author    string `json:"author"`
+	TxID      string `json:"txid"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ContentText is the text of a Content revision, kept in private data collections together with the salt of its hash
+type ContentText struct {
+	ContentID string `json:"contentid"`
+	ReqID     string `json:"reqid"`
+	Revision  int    `json:"revision"`
+	Text      string `json:"text"`
+	Salt      string `json:"salt"`
+}
+
+//Dependents store dependent information written before dependencies became DependencyEdge records
+type Dependents struct {
+	ID     string   `json:"depid"`
+	DepIDs []string `json:"depids"`
+}
+
+// Attributes are the structured properties of a requirement, validated against the RequirementSchema
+type Attributes struct {
+	Priority           string   `json:"priority" metadata:"priority,optional"`
+	Category           string   `json:"category" metadata:"category,optional"`
+	Rationale          string   `json:"rationale" metadata:"rationale,optional"`
+	VerificationMethod string   `json:"verificationmethod" metadata:"verificationmethod,optional"`
+	AcceptanceCriteria []string `json:"acceptancecriteria" metadata:"acceptancecriteria,optional"`
+	ParameterIDs       []string `json:"parameterids" metadata:"parameterids,optional"`
+}
+
+// Requirement an asset
+type Requirement struct {
+	DocType    string     `json:"docType"`
+	ID         string     `json:"id"`
+	Owner      Owner      `json:"owner"`
+	Attributes Attributes `json:"attributes"`
+	ContentID  string     `json:"contentid"`
+	Revision   int        `json:"revision"`
+	Status     string     `json:"status"`
+	CreateTime string     `json:"createtime"`
+	ShareTime  string     `json:"sharetime"`
+	SharedWith []string   `json:"sharedwith"`
+	ParentID   string     `json:"parentid"`
+	DepID      string     `json:"depid"` // legacy Dependents record, see DependencyEdge
+	Reason     string     `json:"reason"`
+
+	// ApprovedRevision is the revision the last approval signed off. A shared requirement revised since then keeps its
+	// suppliers but is not shared further until the new revision is approved.
+	ApprovedRevision int `json:"approvedrevision"`
+
+	// PendingOwner is the organization ownership was offered to, empty without an open offer. OfferedRevisions are
+	// the revisions copied to it for the offer, they are removed again if the offer is not accepted.
+	PendingOwner     string `json:"pendingowner"`
+	OfferedRevisions []int  `json:"offeredrevisions"`
+}
+
+// Alert flags a requirement whose dependency was retired or withdrawn
+type Alert struct {
+	ReqID    string `json:"reqid"`
+	SourceID string `json:"sourceid"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+	TxID     string `json:"txid"`
+	Time     string `json:"time"`
+}
+
+// Set the status to draft on initializing
+func (req *Requirement) setInitialStatus() {
+	req.Status = StatusDraft
+}
+
+// isSharedWith reports whether the requirement was shared with the organization
+func (req *Requirement) isSharedWith(mspID string) bool {
+	return contains(req.SharedWith, mspID)
+}
+
+// Signature is one organization's decision on an approval request
+type Signature struct {
+	MSPID    string `json:"mspid"`
+	Identity string `json:"identity"`
+	Decision string `json:"decision"`
+	Comment  string `json:"comment"`
+	Time     string `json:"time"`
+	TxID     string `json:"txid"`
+}
+
+// ApprovalRequest collects the sign-off of the approver organizations on one revision of a requirement
+type ApprovalRequest struct {
+	ReqID       string       `json:"reqid"`
+	Revision    int          `json:"revision"`
+	Approvers   []string     `json:"approvers"`
+	Quorum      int          `json:"quorum"`
+	Signatures  []*Signature `json:"signatures"`
+	Status      string       `json:"status"`
+	RequestTime string       `json:"requesttime"`
+	TxID        string       `json:"txid"`
+}
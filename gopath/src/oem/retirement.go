@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// RetireRequirement marks a requirement obsolete and flags every requirement depending on it, directly or through
+// other requirements
+func (cc *OEMContract) RetireRequirement(ctx contractapi.TransactionContextInterface, id string,
+	reason string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cc.closeRequirement(ctx, req, StatusRetired, reason, events.AssetRetired, []string{})
+}
+
+// UnshareAsset withdraws a shared requirement from every supplier and flags every requirement depending on it
+func (cc *OEMContract) UnshareAsset(ctx contractapi.TransactionContextInterface, id string,
+	reason string) (*Requirement, error) {
+
+	req, err := cc.GetAsset(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove every revision the suppliers received
+	suppliers := req.SharedWith
+
+	for _, supplierMSP := range suppliers {
+		collection := sharedCollection(req.Owner.MSPID, supplierMSP)
+
+		for rev := 1; rev <= req.Revision; rev++ {
+			key, _ := contentKey(ctx, id, rev)
+
+			if ctx.GetStub().DelPrivateData(collection, key) != nil {
+				return nil, fmt.Errorf("Unable to remove the requirement text from collection %s", collection)
+			}
+		}
+	}
+
+	req.SharedWith = []string{}
+
+	// The suppliers no longer endorse changes
+	err = setEndorsementPolicy(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cc.closeRequirement(ctx, req, StatusWithdrawn, reason, events.AssetUnshared, suppliers)
+}
+
+// GetAlerts returns the alerts raised on requirement id by retired or withdrawn dependencies
+func (cc *OEMContract) GetAlerts(ctx contractapi.TransactionContextInterface, id string) ([]*Alert, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(alertObjectType, []string{id})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	alerts := []*Alert{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read alerts from the world state")
+		}
+
+		alert := new(Alert)
+		err = json.Unmarshal(kv.Value, alert)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Alert", kv.Key)
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// closeRequirement moves the requirement into a closed state, records the reason, flags the whole impact set and
+// raises the event listing it with the owners
+func (cc *OEMContract) closeRequirement(ctx contractapi.TransactionContextInterface, req *Requirement, to string,
+	reason string, eventName string, suppliers []string) (*Requirement, error) {
+
+	err := authorizeOwner(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		return nil, errors.New("A reason is required")
+	}
+
+	err = req.transitionTo(to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Reason = reason
+
+	// A closed requirement cannot change hands
+	err = withdrawOffer(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, _ := json.Marshal(req)
+	err = putRequirementState(ctx, req.ID, reqBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to update the world state")
+	}
+
+	// A requirement built on a dependent of req is just as affected as the dependent itself
+	affected, err := cc.GetImpactSet(ctx, req.ID, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Alerts live under their own keys so the dependents owned by other organizations are not rewritten
+	alertTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Nobody has to review a requirement that no longer applies
+	orgs := []string{}
+
+	for _, dep := range affected {
+		if dep.Owner.MSPID != req.Owner.MSPID && !contains(orgs, dep.Owner.MSPID) {
+			orgs = append(orgs, dep.Owner.MSPID)
+		}
+	}
+
+	sort.Strings(orgs)
+
+	err = closeAcknowledgements(ctx, req.ID, orgs, ackCancelled, fmt.Sprintf("Asset %s is %s: %s", req.ID, to, reason))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dep := range affected {
+		alert := Alert{ReqID: dep.ID, SourceID: req.ID, Status: to, Reason: reason, TxID: ctx.GetStub().GetTxID(),
+			Time: alertTime}
+
+		key, _ := ctx.GetStub().CreateCompositeKey(alertObjectType, []string{dep.ID, req.ID, alert.TxID})
+		alertBytes, _ := json.Marshal(alert)
+
+		if ctx.GetStub().PutState(key, alertBytes) != nil {
+			return nil, errors.New("Unable to commit the alert to the world state")
+		}
+	}
+
+	// Emit the event
+	err = raiseEvent(ctx, eventName, req.ID, events.ClosedPayload{Status: to, Reason: reason, Suppliers: suppliers,
+		Affected: impactPayload(affected)})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"events"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestRetirementAlertsTransitiveDependents(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.create(orgRequirements, "REQ-2", "The brake pedal force shall not exceed 500 N")
+	n.create(orgDesign, "DES-1", "The brake disc diameter shall be 330 mm")
+
+	// DES-1 depends on REQ-2, which depends on REQ-1
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "REQ-2", "REQ-1")
+	})
+
+	n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) error {
+		return n.cc.AddDependency(ctx, "DES-1", "REQ-2")
+	})
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.RetireRequirement(ctx, "REQ-1", "Superseded by the new braking regulation")
+		return err
+	})
+
+	_, body := n.lastEvent()
+	affected := body.(*events.ClosedPayload).Affected
+
+	if len(affected) != 2 || affected[0].ID != "REQ-2" || affected[1].ID != "DES-1" || affected[1].Depth != 2 {
+		t.Fatalf("the event does not list the impact set: %+v", affected)
+	}
+
+	for _, id := range []string{"REQ-2", "DES-1"} {
+		var alerts []*Alert
+
+		n.mustSubmit(orgDesign, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+			alerts, err = n.cc.GetAlerts(ctx, id)
+			return err
+		})
+
+		if len(alerts) != 1 || alerts[0].SourceID != "REQ-1" || alerts[0].Status != StatusRetired {
+			t.Fatalf("expected one retirement alert on %s, got %+v", id, alerts)
+		}
+	}
+}
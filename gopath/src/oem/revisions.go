@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetContentHistory returns every revision of the requirement text, oldest first
+func (cc *OEMContract) GetContentHistory(ctx contractapi.TransactionContextInterface, id string) ([]*Content, error) {
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(contentObjectType, []string{id})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	history := []*Content{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read content revisions from the world state")
+		}
+
+		content := new(Content)
+		err = json.Unmarshal(kv.Value, content)
+
+		if err != nil {
+			return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Content", kv.Key)
+		}
+
+		history = append(history, content)
+	}
+
+	if len(history) == 0 {
+		return nil, fmt.Errorf("No content revisions found for asset with id %s", id)
+	}
+
+	// Composite key attributes sort as strings, so order by revision number here
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+
+	return history, nil
+}
+
+// GetContentRevision returns a single revision of the requirement text
+func (cc *OEMContract) GetContentRevision(ctx contractapi.TransactionContextInterface, id string, rev int) (*Content, error) {
+
+	key, err := contentKey(ctx, id, rev)
+
+	if err != nil {
+		return nil, errors.New("Unable to create content key")
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("Revision %d of asset with id %s does not exist", rev, id)
+	}
+
+	content := new(Content)
+	err = json.Unmarshal(existing, content)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data retrieved from world state for key %s was not of type Content", key)
+	}
+
+	return content, nil
+}
+
+// putContentRevision stores the salted hash of text as a new immutable revision of the requirement written by author,
+// the text and salt go to the private collection of the owning organization
+func (cc *OEMContract) putContentRevision(ctx contractapi.TransactionContextInterface, reqID string, rev int,
+	text string, salt string, ownerMSP string, author string) (*Content, error) {
+
+	key, err := contentKey(ctx, reqID, rev)
+
+	if err != nil {
+		return nil, errors.New("Unable to create content key")
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	if existing != nil {
+		return nil, fmt.Errorf("Revision %d of asset with id %s already exists", rev, reqID)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content := &Content{ID: key, ReqID: reqID, Revision: rev, Hash: contentHash(salt, text), Author: author,
+		TxID: ctx.GetStub().GetTxID(), Timestamp: timestamp}
+
+	contentBytes, _ := json.Marshal(content)
+	err = ctx.GetStub().PutState(key, contentBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the content to the world state")
+	}
+
+	err = putContentText(ctx, privateCollection(ownerMSP),
+		&ContentText{ContentID: key, ReqID: reqID, Revision: rev, Text: text, Salt: salt})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// accept accepts change of requirement id on behalf of mspID
+func (n *testNetwork) accept(mspID string, id string, change *ChangeRequest) {
+	n.t.Helper()
+
+	n.mustSubmit(mspID, n.changeText(mspID, id, change), func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.AcceptChange(ctx, id, change.ID, "")
+		return err
+	})
+}
+
+// history returns the content history of requirement id
+func (n *testNetwork) history(id string) []*Content {
+	n.t.Helper()
+
+	var history []*Content
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		history, err = n.cc.GetContentHistory(ctx, id)
+		return err
+	})
+
+	return history
+}
+
+func TestContentHistoryIsOrderedByRevision(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+
+	// Revision 10 sorts before revision 2 as a key attribute
+	for rev := 2; rev <= 11; rev++ {
+		text := fmt.Sprintf("The vehicle shall stop from 100 km/h within %d m", 40-rev)
+		n.accept(orgRequirements, "REQ-1", n.propose(orgRequirements, "REQ-1", text))
+	}
+
+	history := n.history("REQ-1")
+
+	if len(history) != 11 {
+		t.Fatalf("expected 11 revisions, got %d", len(history))
+	}
+
+	for i, content := range history {
+		if content.Revision != i+1 {
+			t.Fatalf("revision %d is at position %d of the history", content.Revision, i)
+		}
+	}
+}
+
+func TestRevisionIsAuthoredByProposer(t *testing.T) {
+	n := newTestNetwork(t)
+	n.create(orgRequirements, "REQ-1", "The vehicle shall stop from 100 km/h within 40 m")
+	n.approve(orgRequirements, "REQ-1", orgDesign)
+	n.share(orgRequirements, "REQ-1", orgSupplier)
+
+	n.accept(orgRequirements, "REQ-1", n.propose(orgSupplier, "REQ-1", "The vehicle shall stop from 100 km/h within 38 m"))
+
+	history := n.history("REQ-1")
+	owner, _ := (&testIdentity{mspID: orgRequirements}).GetID()
+	supplier, _ := (&testIdentity{mspID: orgSupplier}).GetID()
+
+	if history[0].Author != owner {
+		t.Fatalf("revision 1 should be authored by the creator, got %s", history[0].Author)
+	}
+
+	if history[1].Author != supplier {
+		t.Fatalf("revision 2 should be authored by the proposer, got %s", history[1].Author)
+	}
+}
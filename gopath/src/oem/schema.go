@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// RequirementSchema is the JSON schema requirement attributes must conform to on this channel
+type RequirementSchema struct {
+	Schema     string `json:"schema"`
+	Version    int    `json:"version"`
+	UpdatedBy  string `json:"updatedby"`
+	UpdateTime string `json:"updatetime"`
+	TxID       string `json:"txid"`
+}
+
+// SchemaError is returned when requirement attributes do not conform to the channel schema
+type SchemaError struct {
+	ID       string
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("Attributes of requirement %s do not match the requirement schema: %s", e.ID,
+		strings.Join(e.Problems, "; "))
+}
+
+// SetRequirementSchema stores the JSON schema requirement attributes are validated against. The schema applies to
+// every organization on the channel, so only admins of the governing organization can replace it.
+func (cc *OEMContract) SetRequirementSchema(ctx contractapi.TransactionContextInterface,
+	schema string) (*RequirementSchema, error) {
+
+	err := authorizeAdmin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse schemas that would fail every later validation
+	_, err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load the requirement schema: %s", err.Error())
+	}
+
+	current, err := cc.GetRequirementSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedBy, err := ctx.GetClientIdentity().GetID()
+
+	if err != nil {
+		return nil, errors.New("Unable to read the client identity")
+	}
+
+	updateTime, err := txTimestamp(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	next := &RequirementSchema{Schema: schema, Version: current.Version + 1, UpdatedBy: updatedBy,
+		UpdateTime: updateTime, TxID: ctx.GetStub().GetTxID()}
+
+	key, err := schemaKey(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	schemaBytes, _ := json.Marshal(next)
+	err = ctx.GetStub().PutState(key, schemaBytes)
+
+	if err != nil {
+		return nil, errors.New("Unable to commit the schema to the world state")
+	}
+
+	return next, nil
+}
+
+// GetRequirementSchema returns the schema of this channel, Version 0 means no schema has been set
+func (cc *OEMContract) GetRequirementSchema(ctx contractapi.TransactionContextInterface) (*RequirementSchema, error) {
+
+	key, err := schemaKey(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	schema := new(RequirementSchema)
+
+	if existing == nil {
+		return schema, nil
+	}
+
+	err = json.Unmarshal(existing, schema)
+
+	if err != nil {
+		return nil, errors.New("Data retrieved from world state was not of type RequirementSchema")
+	}
+
+	return schema, nil
+}
+
+// validateAttributes checks the attributes of requirement id against the channel schema, if one is set
+func (cc *OEMContract) validateAttributes(ctx contractapi.TransactionContextInterface, id string,
+	attributes Attributes) error {
+
+	schema, err := cc.GetRequirementSchema(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if schema.Version == 0 {
+		return nil
+	}
+
+	attributeBytes, _ := json.Marshal(attributes)
+
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(schema.Schema),
+		gojsonschema.NewBytesLoader(attributeBytes))
+
+	if err != nil {
+		return fmt.Errorf("Unable to validate the attributes of requirement %s: %s", id, err.Error())
+	}
+
+	if !result.Valid() {
+		problems := []string{}
+
+		for _, problem := range result.Errors() {
+			problems = append(problems, problem.String())
+		}
+
+		return &SchemaError{ID: id, Problems: problems}
+	}
+
+	return nil
+}
+
+// schemaKey is the key of the schema of the current channel
+func schemaKey(ctx contractapi.TransactionContextInterface) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(schemaObjectType, []string{ctx.GetStub().GetChannelID()})
+}
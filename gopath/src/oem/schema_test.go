@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const prioritySchema = `{"type": "object", "properties": {"priority": {"enum": ["high", "medium", "low"]}},
+	"required": ["priority"]}`
+
+func TestOnlyGoverningAdminSetsSchema(t *testing.T) {
+	n := newTestNetwork(t)
+
+	setSchema := func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SetRequirementSchema(ctx, prioritySchema)
+		return err
+	}
+
+	n.mustFail(orgRequirements, nil, setSchema)
+
+	for _, mspID := range []string{orgDesign, orgSupplier} {
+		if n.submitAdmin(mspID, nil, setSchema) == nil {
+			t.Fatalf("an admin of %s replaced the channel schema", mspID)
+		}
+	}
+
+	err := n.submitAdmin(orgRequirements, nil, setSchema)
+
+	if err != nil {
+		t.Fatalf("setting the schema failed: %s", err)
+	}
+}
+
+func TestNewAssetRejectsAttributesOutsideSchema(t *testing.T) {
+	n := newTestNetwork(t)
+
+	err := n.submitAdmin(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SetRequirementSchema(ctx, prioritySchema)
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("setting the schema failed: %s", err)
+	}
+
+	for _, attributes := range []Attributes{{}, {Priority: "urgent"}} {
+		err := n.mustFail(orgDesign, withText("The brake disc diameter shall be 330 mm"),
+			func(ctx contractapi.TransactionContextInterface) error {
+				return n.cc.NewAsset(ctx, "DES-1", attributes)
+			})
+
+		if _, ok := err.(*SchemaError); !ok {
+			t.Fatalf("expected a SchemaError for %+v, got %v", attributes, err)
+		}
+	}
+
+	n.mustSubmit(orgDesign, withText("The brake disc diameter shall be 330 mm"),
+		func(ctx contractapi.TransactionContextInterface) error {
+			return n.cc.NewAsset(ctx, "DES-1", Attributes{Priority: "high"})
+		})
+}
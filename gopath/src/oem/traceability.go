@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// resultPass is the result of passed test cases and runs in the simulation chaincode
+const resultPass = "Pass"
+
+// TestCase is a test case held by the simulation chaincode
+type TestCase struct {
+	ID        string  `json:"testid"`
+	ParamID   string  `json:"paramid"`
+	GoalVal   float32 `json:"goal"`
+	ActualVal float32 `json:"actual"`
+	Result    string  `json:"result"`
+}
+
+// SimulationRun is a run of test cases held by the simulation chaincode
+type SimulationRun struct {
+	RunID     string      `json:"runid"`
+	TestCases []*TestCase `json:"testcases"`
+	Result    string      `json:"result"`
+}
+
+// SimulationReport is a report on runs held by the simulation chaincode
+type SimulationReport struct {
+	ReportID   string           `json:"reportid"`
+	Runs       []*SimulationRun `json:"runs"`
+	Acceptable bool             `json:"acceptable"`
+}
+
+// ParameterEvidence is the simulation evidence of one parameter
+type ParameterEvidence struct {
+	ParamID string              `json:"paramid"`
+	Tests   []*TestCase         `json:"tests"`
+	Runs    []*SimulationRun    `json:"runs"`
+	Reports []*SimulationReport `json:"reports"`
+}
+
+// TraceRow is one path from a requirement to a simulation report, the columns after the point where the chain
+// stops are empty. Verified tells whether a test case of any linked parameter passed in a passed run covered by an
+// acceptable report.
+type TraceRow struct {
+	ReqID      string `json:"reqid"`
+	Status     string `json:"status"`
+	Verified   bool   `json:"verified"`
+	ParamID    string `json:"paramid"`
+	TestID     string `json:"testid"`
+	TestResult string `json:"testresult"`
+	RunID      string `json:"runid"`
+	RunResult  string `json:"runresult"`
+	ReportID   string `json:"reportid"`
+	Acceptable bool   `json:"acceptable"`
+}
+
+// TraceabilityMatrix traces requirements to the simulation evidence verifying them
+type TraceabilityMatrix struct {
+	Rows       []*TraceRow `json:"rows"`
+	Unverified []string    `json:"unverified"`
+}
+
+// traceColumns is the header of the CSV export
+var traceColumns = []string{"reqid", "status", "verified", "paramid", "testid", "testresult", "runid", "runresult",
+	"reportid", "acceptable"}
+
+// GetTraceabilityMatrix traces the listed requirements, or the ones matching the filter, through their parameters to
+// test cases, simulation runs and reports
+func (cc *OEMContract) GetTraceabilityMatrix(ctx contractapi.TransactionContextInterface, reqIDs []string,
+	filter RequirementFilter) (*TraceabilityMatrix, error) {
+
+	reqs, err := cc.selectRequirements(ctx, reqIDs, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &TraceabilityMatrix{Rows: []*TraceRow{}, Unverified: []string{}}
+	evidence := map[string]*ParameterEvidence{}
+
+	for _, req := range reqs {
+		rows := []*TraceRow{}
+
+		if len(req.Attributes.ParameterIDs) == 0 {
+			rows = append(rows, &TraceRow{ReqID: req.ID, Status: req.Status})
+		}
+
+		for _, paramID := range req.Attributes.ParameterIDs {
+			// Parameters are often shared between requirements, ask the simulation chaincode once
+			if evidence[paramID] == nil {
+				evidence[paramID], err = fetchEvidence(ctx, paramID)
+
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			rows = append(rows, traceParameter(req, evidence[paramID])...)
+		}
+
+		verified := false
+
+		for _, row := range rows {
+			verified = verified || row.passed()
+		}
+
+		for _, row := range rows {
+			row.Verified = verified
+		}
+
+		if !verified {
+			matrix.Unverified = append(matrix.Unverified, req.ID)
+		}
+
+		matrix.Rows = append(matrix.Rows, rows...)
+	}
+
+	return matrix, nil
+}
+
+// ExportTraceabilityMatrix returns the traceability matrix as csv or json
+func (cc *OEMContract) ExportTraceabilityMatrix(ctx contractapi.TransactionContextInterface, reqIDs []string,
+	filter RequirementFilter, format string) (string, error) {
+
+	matrix, err := cc.GetTraceabilityMatrix(ctx, reqIDs, filter)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "json":
+		matrixBytes, _ := json.Marshal(matrix)
+
+		return string(matrixBytes), nil
+	case "csv":
+		buffer := new(bytes.Buffer)
+		writer := csv.NewWriter(buffer)
+		writer.Write(traceColumns)
+
+		for _, row := range matrix.Rows {
+			acceptable := ""
+
+			if row.ReportID != "" {
+				acceptable = strconv.FormatBool(row.Acceptable)
+			}
+
+			writer.Write([]string{row.ReqID, row.Status, strconv.FormatBool(row.Verified), row.ParamID, row.TestID,
+				row.TestResult, row.RunID, row.RunResult, row.ReportID, acceptable})
+		}
+
+		writer.Flush()
+
+		if writer.Error() != nil {
+			return "", errors.New("Unable to write the traceability matrix as CSV")
+		}
+
+		return buffer.String(), nil
+	}
+
+	return "", fmt.Errorf("Unknown export format %s, use csv or json", format)
+}
+
+// traceParameter expands one parameter of a requirement into a row per test case, run and report
+func traceParameter(req *Requirement, evidence *ParameterEvidence) []*TraceRow {
+	rows := []*TraceRow{}
+
+	if len(evidence.Tests) == 0 {
+		return append(rows, &TraceRow{ReqID: req.ID, Status: req.Status, ParamID: evidence.ParamID})
+	}
+
+	for _, tc := range evidence.Tests {
+		test := TraceRow{ReqID: req.ID, Status: req.Status, ParamID: evidence.ParamID, TestID: tc.ID,
+			TestResult: tc.Result}
+		runs := 0
+
+		for _, run := range evidence.Runs {
+			if !runIncludes(run, tc.ID) {
+				continue
+			}
+
+			runs++
+			reports := 0
+
+			for _, report := range evidence.Reports {
+				if !reportIncludes(report, run.RunID) {
+					continue
+				}
+
+				reports++
+				row := test
+				row.RunID, row.RunResult = run.RunID, run.Result
+				row.ReportID, row.Acceptable = report.ReportID, report.Acceptable
+				rows = append(rows, &row)
+			}
+
+			if reports == 0 {
+				row := test
+				row.RunID, row.RunResult = run.RunID, run.Result
+				rows = append(rows, &row)
+			}
+		}
+
+		if runs == 0 {
+			rows = append(rows, &test)
+		}
+	}
+
+	return rows
+}
+
+// passed reports whether the row traces a passed test case through a passed run to an acceptable report
+func (row *TraceRow) passed() bool {
+	return row.TestResult == resultPass && row.RunResult == resultPass && row.ReportID != "" && row.Acceptable
+}
+
+// runIncludes reports whether the run executed the test case
+func runIncludes(run *SimulationRun, testID string) bool {
+	for _, tc := range run.TestCases {
+		if tc.ID == testID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportIncludes reports whether the report covers the run
+func reportIncludes(report *SimulationReport, runID string) bool {
+	for _, run := range report.Runs {
+		if run.RunID == runID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchEvidence reads the test cases, runs and reports of a parameter from the simulation chaincode named in the
+// chaincode configuration
+func fetchEvidence(ctx contractapi.TransactionContextInterface, paramID string) (*ParameterEvidence, error) {
+
+	config, err := getChaincodeConfig(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := [][]byte{[]byte("GetParameterEvidence"), []byte(paramID)}
+	response := ctx.GetStub().InvokeChaincode(config.SimChaincode, args, "")
+
+	if response.Status != shim.OK {
+		return nil, fmt.Errorf("Evidence for parameter %s could not be read from %s: %s", paramID,
+			config.SimChaincode, response.Message)
+	}
+
+	evidence := new(ParameterEvidence)
+	err = json.Unmarshal(response.Payload, evidence)
+
+	if err != nil {
+		return nil, fmt.Errorf("Data returned by %s for parameter %s was not of type ParameterEvidence",
+			config.SimChaincode, paramID)
+	}
+
+	return evidence, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func TestVerifiedNeedsPassingTestInAcceptableReport(t *testing.T) {
+	n := newTestNetwork(t)
+
+	params := map[string]interface{}{}
+	passed := &TestCase{ID: "T-1", ParamID: "P-1", Result: resultPass}
+	failed := &TestCase{ID: "T-2", ParamID: "P-2", Result: "Fail"}
+	flaky := &TestCase{ID: "T-3", ParamID: "P-3", Result: resultPass}
+	passedRun := &SimulationRun{RunID: "R-1", TestCases: []*TestCase{passed}, Result: resultPass}
+	failedRun := &SimulationRun{RunID: "R-2", TestCases: []*TestCase{failed}, Result: "Fail"}
+	flakyRun := &SimulationRun{RunID: "R-3", TestCases: []*TestCase{flaky}, Result: resultPass}
+
+	evidence := map[string]interface{}{
+		"P-1": ParameterEvidence{ParamID: "P-1", Tests: []*TestCase{passed}, Runs: []*SimulationRun{passedRun},
+			Reports: []*SimulationReport{{ReportID: "REP-1", Runs: []*SimulationRun{passedRun}, Acceptable: true}}},
+		"P-2": ParameterEvidence{ParamID: "P-2", Tests: []*TestCase{failed}, Runs: []*SimulationRun{failedRun},
+			Reports: []*SimulationReport{{ReportID: "REP-2", Runs: []*SimulationRun{failedRun}}}},
+		"P-3": ParameterEvidence{ParamID: "P-3", Tests: []*TestCase{flaky}, Runs: []*SimulationRun{flakyRun},
+			Reports: []*SimulationReport{{ReportID: "REP-3", Runs: []*SimulationRun{flakyRun}}}},
+		"P-4": ParameterEvidence{ParamID: "P-4"},
+	}
+
+	for _, id := range []string{"P-1", "P-2", "P-3", "P-4"} {
+		params[id] = Parameter{ParamID: id}
+	}
+
+	n.deploy(defaultParamChaincode, &fakeChaincode{payloads: params})
+	n.deploy("simulation", &fakeChaincode{payloads: evidence})
+
+	err := n.submitAdmin(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := n.cc.SetChaincodeConfig(ctx, "", "simulation")
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("configuring the chaincode names failed: %s", err)
+	}
+
+	reqIDs := []string{"REQ-1", "REQ-2", "REQ-3", "REQ-4"}
+
+	for i, id := range reqIDs {
+		n.create(orgRequirements, id, "The brake disc diameter shall be 330 mm")
+
+		n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) error {
+			_, err := n.cc.LinkParameter(ctx, id, []string{"P-1", "P-2", "P-3", "P-4"}[i])
+			return err
+		})
+	}
+
+	var matrix *TraceabilityMatrix
+
+	n.mustSubmit(orgRequirements, nil, func(ctx contractapi.TransactionContextInterface) (err error) {
+		matrix, err = n.cc.GetTraceabilityMatrix(ctx, reqIDs, RequirementFilter{})
+		return err
+	})
+
+	// A failed test, a report that was not accepted and a parameter without tests do not verify anything
+	if !reflect.DeepEqual(matrix.Unverified, []string{"REQ-2", "REQ-3", "REQ-4"}) {
+		t.Fatalf("unexpected unverified requirements %v", matrix.Unverified)
+	}
+
+	if len(matrix.Rows) != 4 || !matrix.Rows[0].Verified || matrix.Rows[0].ReportID != "REP-1" {
+		t.Fatalf("unexpected rows %+v", matrix.Rows[0])
+	}
+}
@@ -1,6 +0,0 @@
-package main
-
-//NewPackageCreated for event newAsset
-type NewPackageCreated struct {
-	AssetID string `json:"assetid"`
-}
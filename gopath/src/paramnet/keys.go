@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Composite key namespaces, every asset type is stored under its own object type so a package ID can never collide
+// with a parameter ID
+const (
+	parameterObjectType = "parameter"
+	packageObjectType   = "package"
+)
+
+// getAssetState reads the asset id stored under objectType
+func getAssetState(ctx contractapi.TransactionContextInterface, objectType string, id string) ([]byte, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{id})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.GetStub().GetState(key)
+}
+
+// putAssetState writes the asset id under objectType
+func putAssetState(ctx contractapi.TransactionContextInterface, objectType string, id string, value []byte) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{id})
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, value)
+}
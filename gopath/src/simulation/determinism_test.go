@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordingStub is a MockStub that records the write set and the event of one proposal
+type recordingStub struct {
+	*shimtest.MockStub
+
+	writes map[string][]byte
+	event  *peer.ChaincodeEvent
+}
+
+func (s *recordingStub) PutState(key string, value []byte) error {
+	s.writes[key] = value
+
+	return s.MockStub.PutState(key, value)
+}
+
+func (s *recordingStub) DelState(key string) error {
+	s.writes[key] = nil
+
+	return s.MockStub.DelState(key)
+}
+
+func (s *recordingStub) SetEvent(name string, payload []byte) error {
+	s.event = &peer.ChaincodeEvent{EventName: name, Payload: payload}
+
+	return nil
+}
+
+// testIdentity is a client of one organization
+type testIdentity struct {
+	mspID string
+}
+
+func (id *testIdentity) GetID() (string, error) {
+	return "x509::CN=user1@" + id.mspID, nil
+}
+
+func (id *testIdentity) GetMSPID() (string, error) {
+	return id.mspID, nil
+}
+
+func (id *testIdentity) GetAttributeValue(name string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (id *testIdentity) AssertAttributeValue(name string, value string) error {
+	return fmt.Errorf("attribute %s is not %s", name, value)
+}
+
+func (id *testIdentity) GetX509Certificate() (*x509.Certificate, error) {
+	return &x509.Certificate{}, nil
+}
+
+// endorse simulates a proposal the way one peer does, on its own copy of the ledger, and returns what it wrote
+func endorse(t *testing.T, state map[string][]byte,
+	fn func(ctx contractapi.TransactionContextInterface) error) *recordingStub {
+
+	t.Helper()
+
+	stub := &recordingStub{MockStub: shimtest.NewMockStub("simcc", nil), writes: map[string][]byte{}}
+
+	stub.MockTransactionStart("setup")
+
+	for key, value := range state {
+		stub.MockStub.PutState(key, value)
+	}
+
+	stub.MockTransactionEnd("setup")
+
+	stub.MockTransactionStart("0b2c6f0e7a1d4c33")
+	stub.TxTimestamp = &timestamppb.Timestamp{Seconds: 1600000000, Nanos: 500}
+
+	ctx := new(contractapi.TransactionContext)
+	ctx.SetStub(stub)
+	ctx.SetClientIdentity(&testIdentity{mspID: "SimulationGroupMSP"})
+
+	err := fn(ctx)
+
+	if err != nil {
+		t.Fatalf("proposal failed: %s", err)
+	}
+
+	return stub
+}
+
+// checkDeterministic endorses the proposal twice and fails unless both endorsements wrote the same
+func checkDeterministic(t *testing.T, state map[string][]byte,
+	fn func(ctx contractapi.TransactionContextInterface) error) map[string][]byte {
+
+	t.Helper()
+
+	first := endorse(t, state, fn)
+	second := endorse(t, state, fn)
+
+	if !reflect.DeepEqual(first.writes, second.writes) {
+		t.Fatalf("the endorsements wrote different values:\n%q\n%q", first.writes, second.writes)
+	}
+
+	if !reflect.DeepEqual(first.event, second.event) {
+		t.Fatalf("the endorsements raised different events:\n%v\n%v", first.event, second.event)
+	}
+
+	// The ledger as it is after the proposal commits
+	committed := map[string][]byte{}
+
+	for key, value := range first.State {
+		committed[key] = value
+	}
+
+	return committed
+}
+
+func TestTransactionsAreDeterministic(t *testing.T) {
+	sc := new(SimulationContract)
+	state := map[string][]byte{}
+
+	state = checkDeterministic(t, state, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := sc.CreateTest(ctx, "T-1", "P-1", 330, 330.5)
+		return err
+	})
+
+	state = checkDeterministic(t, state, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := sc.CreateTest(ctx, "T-2", "P-1", 330, 334)
+		return err
+	})
+
+	state = checkDeterministic(t, state, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := sc.CreateRun(ctx, "RUN-1", []string{"T-1", "T-2"}, 2, 1)
+		return err
+	})
+
+	checkDeterministic(t, state, func(ctx contractapi.TransactionContextInterface) error {
+		_, err := sc.CreateReport(ctx, "REP-1", []string{"RUN-1"}, 1, 1)
+		return err
+	})
+}
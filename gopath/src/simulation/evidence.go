@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetParameterEvidence returns the test cases of a parameter, the runs that include them and the reports that
+// include those runs
+func (sc *SimulationContract) GetParameterEvidence(ctx contractapi.TransactionContextInterface,
+	paramID string) (*ParameterEvidence, error) {
+
+	evidence := &ParameterEvidence{ParamID: paramID, Tests: []*TestCase{}, Runs: []*SimulationRun{},
+		Reports: []*SimulationReport{}}
+
+	testIDs, err := listIndexEntries(ctx, testByParamObjectType, paramID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	runIDs := []string{}
+	seenRuns := map[string]bool{}
+
+	for _, testID := range testIDs {
+		tc, err := sc.findTest(ctx, testID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		evidence.Tests = append(evidence.Tests, tc)
+
+		ids, err := listIndexEntries(ctx, runByTestObjectType, testID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, runID := range ids {
+			if !seenRuns[runID] {
+				seenRuns[runID] = true
+				runIDs = append(runIDs, runID)
+			}
+		}
+	}
+
+	reportIDs := []string{}
+	seenReports := map[string]bool{}
+
+	for _, runID := range runIDs {
+		run, err := sc.findRun(ctx, runID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		evidence.Runs = append(evidence.Runs, run)
+
+		ids, err := listIndexEntries(ctx, reportByRunObjectType, runID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reportID := range ids {
+			if !seenReports[reportID] {
+				seenReports[reportID] = true
+				reportIDs = append(reportIDs, reportID)
+			}
+		}
+	}
+
+	for _, reportID := range reportIDs {
+		report, err := sc.findReport(ctx, reportID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		evidence.Reports = append(evidence.Reports, report)
+	}
+
+	return evidence, nil
+}
+
+// GetEvaluateTransactions returns functions of SimulationContract not to be tagged as submit
+func (sc *SimulationContract) GetEvaluateTransactions() []string {
+	return []string{"GetParameterEvidence"}
+}
+
+//findReport returns the report
+func (sc *SimulationContract) findReport(ctx contractapi.TransactionContextInterface,
+	reportID string) (*SimulationReport, error) {
+
+	existing, err := getAssetState(ctx, reportObjectType, reportID)
+
+	if err != nil {
+		return nil, errors.New("Unable to communicate with World state")
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("The report with ID %s does not exist", reportID)
+	}
+
+	report := new(SimulationReport)
+
+	err = json.Unmarshal(existing, report)
+
+	if err != nil {
+		return nil, errors.New("Unable to convert JSON to structure")
+	}
+
+	return report, nil
+}
+
+// indexTest records the test case under its parameter
+func indexTest(ctx contractapi.TransactionContextInterface, tc *TestCase) error {
+	if putIndexEntry(ctx, testByParamObjectType, tc.ParamID, tc.ID) != nil {
+		return errors.New("Unable to save the test case index to the World state")
+	}
+
+	return nil
+}
+
+// indexRun records the run under each of its test cases
+func indexRun(ctx contractapi.TransactionContextInterface, run *SimulationRun) error {
+	for _, tc := range run.TestCases {
+		if putIndexEntry(ctx, runByTestObjectType, tc.ID, run.RunID) != nil {
+			return errors.New("Unable to save the run index to the World state")
+		}
+	}
+
+	return nil
+}
+
+// indexReport records the report under each of its runs
+func indexReport(ctx contractapi.TransactionContextInterface, report *SimulationReport) error {
+	for _, run := range report.Runs {
+		if putIndexEntry(ctx, reportByRunObjectType, run.RunID, report.ReportID) != nil {
+			return errors.New("Unable to save the report index to the World state")
+		}
+	}
+
+	return nil
+}
+
+// indexAsset writes the reverse index entries of a stored test case, run or report
+func indexAsset(ctx contractapi.TransactionContextInterface, objectType string, value []byte) error {
+	switch objectType {
+	case testCaseObjectType:
+		tc := new(TestCase)
+
+		if json.Unmarshal(value, tc) != nil {
+			return errors.New("Unable to convert JSON to structure")
+		}
+
+		return indexTest(ctx, tc)
+	case runObjectType:
+		run := new(SimulationRun)
+
+		if json.Unmarshal(value, run) != nil {
+			return errors.New("Unable to convert JSON to structure")
+		}
+
+		return indexRun(ctx, run)
+	case reportObjectType:
+		report := new(SimulationReport)
+
+		if json.Unmarshal(value, report) != nil {
+			return errors.New("Unable to convert JSON to structure")
+		}
+
+		return indexReport(ctx, report)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Composite key namespaces, every asset type is stored under its own object type so a run can never be loaded as a
+// test case or a report
+const (
+	testCaseObjectType = "testcase"
+	runObjectType      = "run"
+	reportObjectType   = "report"
+
+	// Reverse indexes from a parameter to its test cases, a test case to its runs and a run to its reports
+	testByParamObjectType = "testbyparam"
+	runByTestObjectType   = "runbytest"
+	reportByRunObjectType = "reportbyrun"
+)
+
+// getAssetState reads the asset id stored under objectType
+func getAssetState(ctx contractapi.TransactionContextInterface, objectType string, id string) ([]byte, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{id})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.GetStub().GetState(key)
+}
+
+// putAssetState writes the asset id under objectType
+func putAssetState(ctx contractapi.TransactionContextInterface, objectType string, id string, value []byte) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{id})
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, value)
+}
+
+// putIndexEntry records that id belongs to from in the reverse index objectType
+func putIndexEntry(ctx contractapi.TransactionContextInterface, objectType string, from string, id string) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{from, id})
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, []byte(id))
+}
+
+// listIndexEntries returns the ids recorded for from in the reverse index objectType
+func listIndexEntries(ctx contractapi.TransactionContextInterface, objectType string, from string) ([]string, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{from})
+
+	if err != nil {
+		return nil, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	ids := []string{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, errors.New("Unable to read the index from the world state")
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+
+		if err != nil || len(attributes) != 2 {
+			return nil, fmt.Errorf("Malformed index key %s", kv.Key)
+		}
+
+		ids = append(ids, attributes[1])
+	}
+
+	return ids, nil
+}
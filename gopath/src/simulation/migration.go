@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// MigrateKeys moves up to limit test cases, runs and reports stored under plain keys into their composite key
+// namespaces. Call it until it returns 0.
+func (sc *SimulationContract) MigrateKeys(ctx contractapi.TransactionContextInterface, limit int) (int, error) {
+
+	err := ctx.GetClientIdentity().AssertAttributeValue("role", "admin")
+
+	if err != nil {
+		return 0, errors.New("Only clients enrolled with role=admin can call this transaction")
+	}
+
+	// An open range only returns plain keys, composite keys are never visited
+	iterator, err := ctx.GetStub().GetStateByRange("", "")
+
+	if err != nil {
+		return 0, errors.New("Unable to interact with the world state")
+	}
+
+	defer iterator.Close()
+
+	migrated := 0
+
+	for iterator.HasNext() && migrated < limit {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return 0, errors.New("Unable to read from the world state")
+		}
+
+		fields := map[string]json.RawMessage{}
+
+		if json.Unmarshal(kv.Value, &fields) != nil {
+			continue
+		}
+
+		// Each type carries its own ID field
+		var objectType string
+
+		switch {
+		case fields["testid"] != nil:
+			objectType = testCaseObjectType
+		case fields["runid"] != nil:
+			objectType = runObjectType
+		case fields["reportid"] != nil:
+			objectType = reportObjectType
+		default:
+			continue
+		}
+
+		if putAssetState(ctx, objectType, kv.Key, kv.Value) != nil || ctx.GetStub().DelState(kv.Key) != nil {
+			return 0, errors.New("Unable to update the world state")
+		}
+
+		// Legacy records predate the reverse indexes
+		err = indexAsset(ctx, objectType, kv.Value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
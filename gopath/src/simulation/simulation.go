@@ -1,24 +1,32 @@
-package main
-
-//TestCase represets test data
-type TestCase struct {
-	ID        string  `json:"testid"`
-	ParamID   string  `json:"paramid"`
-	GoalVal   float32 `json:"goal"`
-	ActualVal float32 `json:"actual"`
-	Result    string  `json:"result"`
-}
-
-//SimulationRun represents collection of test cases
-type SimulationRun struct {
-	RunID     string      `json:"runid"`
-	TestCases []*TestCase `json:"testcases"`
-	Result    string      `json:"result"`
-}
-
-// SimulationReport summarises the activity
-type SimulationReport struct {
-	ReportID   string           `json:"reportid"`
-	Runs       []*SimulationRun `json:"runs"`
-	Acceptable bool             `json:"acceptable"`
-}
+package main
+
+//TestCase represets test data
+type TestCase struct {
+	ID        string  `json:"testid"`
+	ParamID   string  `json:"paramid"`
+	GoalVal   float32 `json:"goal"`
+	ActualVal float32 `json:"actual"`
+	Result    string  `json:"result"`
+}
+
+//SimulationRun represents collection of test cases
+type SimulationRun struct {
+	RunID     string      `json:"runid"`
+	TestCases []*TestCase `json:"testcases"`
+	Result    string      `json:"result"`
+}
+
+// SimulationReport summarises the activity
+type SimulationReport struct {
+	ReportID   string           `json:"reportid"`
+	Runs       []*SimulationRun `json:"runs"`
+	Acceptable bool             `json:"acceptable"`
+}
+
+// ParameterEvidence is every test case of a parameter with the runs and reports that include them
+type ParameterEvidence struct {
+	ParamID string              `json:"paramid"`
+	Tests   []*TestCase         `json:"tests"`
+	Runs    []*SimulationRun    `json:"runs"`
+	Reports []*SimulationReport `json:"reports"`
+}